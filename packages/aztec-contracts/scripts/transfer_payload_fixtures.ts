@@ -0,0 +1,60 @@
+// Regenerates the relayer's payload fixtures from the MultiSig contract's encoder by running the
+// transfer_payload_fixtures Noir test and capturing what it prints.
+import { execSync } from "node:child_process";
+import { writeFileSync } from "node:fs";
+import { fileURLToPath } from "node:url";
+
+const OUTPUT = fileURLToPath(
+  new URL("../../relayer/payload/testdata/transfer_payloads.json", import.meta.url),
+);
+// Lines printed per fixture after its "fixture <name>" marker
+const FIELDS = ["txId", "token", "recipient", "amount", "targetChain", "payload"] as const;
+
+// Numbers may be printed as hex or decimal depending on the nargo version
+function numbers(line: string): bigint[] {
+  return (line.match(/0x[0-9a-f]+|\d+/gi) ?? []).map((n) => BigInt(n));
+}
+
+function hex(bytes: bigint[]): string {
+  return "0x" + bytes.map((b) => b.toString(16).padStart(2, "0")).join("");
+}
+
+function toBigInt(bytes: bigint[]): bigint {
+  return bytes.reduce((acc, b) => (acc << 8n) | b, 0n);
+}
+
+function main() {
+  const nargo = process.env.AZTEC_NARGO ?? "aztec-nargo";
+  const output = execSync(`${nargo} test --show-output transfer_payload_fixtures`, {
+    cwd: fileURLToPath(new URL("..", import.meta.url)),
+    encoding: "utf8",
+  });
+
+  const lines = output.split("\n").map((line) => line.trim().replace(/^"|"$/g, ""));
+  const fixtures = [];
+  for (let i = 0; i < lines.length; i++) {
+    const marker = lines[i].match(/^fixture (\w+)$/);
+    if (!marker) continue;
+
+    const values = Object.fromEntries(FIELDS.map((field, j) => [field, numbers(lines[i + 1 + j])]));
+    i += FIELDS.length;
+
+    fixtures.push({
+      name: marker[1],
+      txId: hex(values.txId),
+      token: hex(values.token.slice(12)),
+      recipient: hex(values.recipient.slice(12)),
+      amount: toBigInt(values.amount).toString(),
+      targetChain: Number(values.targetChain[0]),
+      payload: hex([...values.txId, ...values.payload]),
+    });
+  }
+  if (fixtures.length === 0) {
+    throw new Error(`no fixtures found in test output:\n${output}`);
+  }
+
+  writeFileSync(OUTPUT, JSON.stringify(fixtures, null, 2) + "\n");
+  console.log(`wrote ${fixtures.length} fixtures to ${OUTPUT}`);
+}
+
+main();
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/wormhole-foundation/wormhole/aztec/relayer/relayer"
+)
+
+// runDLQCommand implements the "dlq" subcommand for inspecting the dead-letter queue of a
+// stopped relayer. A running relayer holds the store lock; use its own methods instead.
+func runDLQCommand(config relayer.Config, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: relayer dlq list | relayer dlq requeue <vaaHash>...")
+	}
+	store, err := relayer.NewBoltVAAStore(config.StorePath)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	switch args[0] {
+	case "list":
+		records, err := store.List(relayer.VAAStatusDeadLettered)
+		if err != nil {
+			return err
+		}
+		for _, rec := range records {
+			fmt.Fprintf(os.Stdout, "%s\tchain=%d\temitter=%s\tsequence=%d\tattempts=%d\terror=%s\n",
+				rec.Key, rec.ChainID, rec.EmitterHex, rec.Sequence, rec.Attempts, rec.LastError)
+		}
+		return nil
+	case "requeue":
+		if len(args) < 2 {
+			return fmt.Errorf("usage: relayer dlq requeue <vaaHash>...")
+		}
+		for _, key := range args[1:] {
+			if err := relayer.RequeueDeadLettered(store, key); err != nil {
+				return fmt.Errorf("failed to requeue %s: %v", key, err)
+			}
+			fmt.Fprintf(os.Stdout, "requeued %s\n", key)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown dlq command %q", args[0])
+	}
+}
@@ -0,0 +1,160 @@
+// Command relayer runs the Aztec Wormhole relayer configured by a YAML file, the .env file
+// and the environment.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/joho/godotenv"
+	"github.com/wormhole-foundation/wormhole/aztec/relayer/relayer"
+	"go.uber.org/zap"
+)
+
+// Global logger for initial setup
+var logger *zap.Logger
+
+// Initialize global logger
+func initLogger() {
+	var err error
+
+	// Check for LOG_LEVEL environment variable
+	logLevel := os.Getenv("LOG_LEVEL")
+
+	var config zap.Config
+	if logLevel == "debug" {
+		config = zap.NewDevelopmentConfig()
+		config.Level = zap.NewAtomicLevelAt(zap.DebugLevel)
+	} else {
+		config = zap.NewProductionConfig()
+		if logLevel == "info" {
+			config.Level = zap.NewAtomicLevelAt(zap.InfoLevel)
+		} else if logLevel == "warn" {
+			config.Level = zap.NewAtomicLevelAt(zap.WarnLevel)
+		} else if logLevel == "error" {
+			config.Level = zap.NewAtomicLevelAt(zap.ErrorLevel)
+		}
+	}
+
+	logger, err = config.Build()
+	if err != nil {
+		// Fallback to standard logger if zap fails
+		fmt.Printf("Failed to initialize zap logger: %v\n", err)
+		logger = zap.NewExample()
+	}
+}
+
+// shellEnv holds the variables set before the .env file was first loaded. Like the initial load,
+// a reload of the .env file does not override them.
+var shellEnv = make(map[string]bool)
+
+// recordShellEnv remembers which variables come from the environment rather than the .env file
+func recordShellEnv() {
+	for _, kv := range os.Environ() {
+		key, _, _ := strings.Cut(kv, "=")
+		shellEnv[key] = true
+	}
+}
+
+// reloadDotEnv re-reads the .env file into the environment, skipping variables set by the shell
+func reloadDotEnv() error {
+	values, err := godotenv.Read()
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	for key, value := range values {
+		if !shellEnv[key] {
+			os.Setenv(key, value)
+		}
+	}
+	return nil
+}
+
+// reloadRouteEmitters re-reads the config file, .env file and environment and applies the
+// route emitter allowlists
+func reloadRouteEmitters(r *relayer.Relayer, configPath string) {
+	if err := reloadDotEnv(); err != nil {
+		logger.Warn("Error reloading .env file", zap.Error(err))
+	}
+
+	config, err := relayer.LoadConfig(configPath)
+	if err != nil {
+		logger.Error("Invalid configuration after reload, keeping current emitters", zap.Error(err))
+		return
+	}
+	if err := r.UpdateRouteEmitters(config.RouteTable()); err != nil {
+		logger.Error("Rejected reloaded emitters, keeping current emitters", zap.Error(err))
+	}
+}
+
+func main() {
+	// Initialize the logger first
+	recordShellEnv()
+	initLogger()
+	defer logger.Sync()
+
+	configPath := flag.String("config", "", "YAML config file; environment variables override its values")
+	flag.Parse()
+
+	logger.Info("Starting Aztec Wormhole relayer", zap.String("config", *configPath))
+
+	// Load and validate configuration before connecting to anything
+	config, err := relayer.LoadConfig(*configPath)
+	if err != nil {
+		logger.Fatal("Failed to load configuration", zap.Error(err))
+	}
+
+	// Offline dead-letter queue maintenance
+	if args := flag.Args(); len(args) > 0 && args[0] == "dlq" {
+		if err := runDLQCommand(config, args[1:]); err != nil {
+			logger.Fatal("dlq command failed", zap.Error(err))
+		}
+		return
+	}
+
+	logger.Debug("Configuration loaded", zap.Stringer("config", config))
+
+	// Create relayer
+	r, err := relayer.New(config, relayer.WithLogger(logger))
+	if err != nil {
+		logger.Fatal("Failed to initialize relayer", zap.Error(err))
+	}
+	defer r.Close()
+
+	// Setup context with cancellation
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Reload emitter allowlists (and with them the spy filters) on SIGHUP
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	go func() {
+		for range hup {
+			logger.Info("Received SIGHUP, reloading emitter allowlists")
+			reloadRouteEmitters(r, *configPath)
+		}
+	}()
+
+	// Handle graceful shutdown
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-c
+		logger.Info("Received shutdown signal")
+		cancel()
+	}()
+
+	// Start the relayer
+	if err := r.Start(ctx); err != nil {
+		logger.Fatal("Relayer stopped with error", zap.Error(err))
+	}
+}
@@ -0,0 +1,239 @@
+// Package evm delivers VAAs to Treasury contracts on EVM chains. Client sends verify()
+// transactions with local nonce management, waits for confirmed receipts and replaces
+// transactions that get stuck; Signer abstracts where the sending key is held.
+package evm
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"go.uber.org/zap"
+)
+
+// Options tunes how Client tracks the transactions it sends
+type Options struct {
+	Confirmations   uint64        // Blocks (including the inclusion block) before a receipt is final
+	ReceiptTimeout  time.Duration // How long to wait for a sent transaction to be confirmed
+	StuckAfter      time.Duration // Pending time before a transaction is re-sent with bumped fees (0 disables)
+	FeeBumpPercent  int           // Fee increase per replacement, at least 10%
+	MaxFeePerGas    *big.Int      // Ceiling for bumped fee caps in wei (nil means unbounded)
+	GasMultiplier   float64       // Safety margin applied to estimated gas
+	GasLimitFloor   uint64        // Minimum gas limit
+	GasLimitCeiling uint64        // Maximum gas limit (0 means unbounded)
+}
+
+// Client handles interactions with EVM-compatible blockchains (Arbitrum)
+type Client struct {
+	chainID uint16 // Wormhole chain ID, used in logs and metric labels
+	client  *ethclient.Client
+	signer  Signer
+	address common.Address
+	nonces  *NonceManager
+	txs     *txTracker
+	opts    Options
+	logger  *zap.Logger
+}
+
+// NewClient creates a new client for EVM-compatible blockchains
+func NewClient(chainID uint16, rpcURL string, signer Signer, opts Options, logger *zap.Logger) (*Client, error) {
+	client := &Client{
+		chainID: chainID,
+		opts:    opts,
+		txs:     newTxTracker(),
+		logger:  logger.With(zap.String("component", "Client"), zap.Uint16("chain", chainID)),
+	}
+
+	client.logger.Info("Connecting to EVM chain", zap.String("rpcURL", rpcURL))
+	ethClient, err := ethclient.Dial(rpcURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to EVM node: %v", err)
+	}
+
+	client.client = ethClient
+	client.signer = signer
+	client.address = signer.Address()
+	client.nonces = NewNonceManager(ethClient, client.address, logger)
+	client.nonces.onRewind = client.txs.dropFrom
+
+	return client, nil
+}
+
+// GetAddress returns the public address for this client
+func (c *Client) GetAddress() common.Address {
+	return c.address
+}
+
+// chainLabel returns the chain ID as a metric label value
+func (c *Client) chainLabel() string {
+	return strconv.Itoa(int(c.chainID))
+}
+
+// Balance returns the native balance of the relayer wallet and updates the balance gauge
+func (c *Client) Balance(ctx context.Context) (*big.Int, error) {
+	balance, err := c.client.BalanceAt(ctx, c.address, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get balance: %v", err)
+	}
+	walletBalanceWei.WithLabelValues(c.chainLabel()).Set(weiToFloat(balance))
+	return balance, nil
+}
+
+// SendVerifyTransaction sends a transaction to the verify function to process and store a VAA
+func (c *Client) SendVerifyTransaction(ctx context.Context, targetContract string, vaaBytes []byte) (string, error) {
+	c.logger.Debug("Sending verify transaction to EVM", zap.Int("vaaLength", len(vaaBytes)))
+
+	// Contract ABI for the verify function
+	const abiJSON = `[{
+        "inputs": [
+            {"internalType": "bytes", "name": "encodedVm", "type": "bytes"}
+        ],
+        "name": "verify",
+        "outputs": [],
+        "stateMutability": "nonpayable",
+        "type": "function"
+    }]`
+
+	parsedABI, err := abi.JSON(strings.NewReader(abiJSON))
+	if err != nil {
+		return "", fmt.Errorf("ABI parse error: %v", err)
+	}
+
+	// Pack the function call data
+	data, err := parsedABI.Pack("verify", vaaBytes)
+	if err != nil {
+		return "", fmt.Errorf("ABI pack error: %v", err)
+	}
+
+	// Get the chain ID
+	chainID, err := c.client.NetworkID(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get chain ID: %v", err)
+	}
+
+	// Get the current base fee from the latest block header
+	header, err := c.client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to get latest block header: %v", err)
+	}
+
+	// Calculate gas fees with buffer for EIP-1559
+	// Use 2x base fee as max fee to handle fluctuations
+	baseFee := header.BaseFee
+	maxPriorityFeePerGas := big.NewInt(100000000) // 0.1 gwei tip
+	maxFeePerGas := new(big.Int).Mul(baseFee, big.NewInt(2))
+	maxFeePerGas.Add(maxFeePerGas, maxPriorityFeePerGas)
+
+	// Estimate the gas limit; a failing estimate means verify() would revert, so don't pay for it
+	targetAddr := common.HexToAddress(targetContract)
+	gasLimit, err := c.estimateGasLimit(ctx, targetAddr, data)
+	if err != nil {
+		return "", err
+	}
+
+	// Create, sign and send the transaction with the next local nonce
+	var signedTx *types.Transaction
+	err = c.nonces.Send(ctx, func(nonce uint64) error {
+		tx := types.NewTx(&types.DynamicFeeTx{
+			ChainID:   chainID,
+			Nonce:     nonce,
+			GasTipCap: maxPriorityFeePerGas,
+			GasFeeCap: maxFeePerGas,
+			Gas:       gasLimit,
+			To:        &targetAddr,
+			Value:     big.NewInt(0),
+			Data:      data,
+		})
+
+		var err error
+		signedTx, err = c.signer.SignTx(ctx, tx, chainID)
+		if err != nil {
+			return fmt.Errorf("failed to sign transaction: %v", err)
+		}
+
+		return c.client.SendTransaction(ctx, signedTx)
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to send transaction: %v", err)
+	}
+
+	// Track it so MonitorTransactions can replace it if it gets stuck
+	c.txs.add(signedTx)
+
+	return signedTx.Hash().Hex(), nil
+}
+
+// CallContract executes a read-only call on the chain, so the client can back contract
+// readers such as EVMGuardianSetProvider
+func (c *Client) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	return c.client.CallContract(ctx, msg, blockNumber)
+}
+
+// IsMessageProcessed asks the target Treasury whether the message identified by emitter chain,
+// emitter address and sequence has already been delivered
+func (c *Client) IsMessageProcessed(ctx context.Context, targetContract string, emitterChain uint16, emitterAddress [32]byte, sequence uint64) (bool, error) {
+	// Contract ABI for the isMessageProcessed view
+	const abiJSON = `[{
+        "inputs": [
+            {"internalType": "uint16", "name": "emitterChainId", "type": "uint16"},
+            {"internalType": "bytes32", "name": "emitterAddress", "type": "bytes32"},
+            {"internalType": "uint64", "name": "sequence", "type": "uint64"}
+        ],
+        "name": "isMessageProcessed",
+        "outputs": [{"internalType": "bool", "name": "", "type": "bool"}],
+        "stateMutability": "view",
+        "type": "function"
+    }]`
+
+	parsedABI, err := abi.JSON(strings.NewReader(abiJSON))
+	if err != nil {
+		return false, fmt.Errorf("ABI parse error: %v", err)
+	}
+
+	data, err := parsedABI.Pack("isMessageProcessed", emitterChain, emitterAddress, sequence)
+	if err != nil {
+		return false, fmt.Errorf("ABI pack error: %v", err)
+	}
+
+	targetAddr := common.HexToAddress(targetContract)
+	result, err := c.client.CallContract(ctx, ethereum.CallMsg{To: &targetAddr, Data: data}, nil)
+	if err != nil {
+		return false, fmt.Errorf("isMessageProcessed call failed: %v", err)
+	}
+
+	var processed bool
+	if err := parsedABI.UnpackIntoInterface(&processed, "isMessageProcessed", result); err != nil {
+		return false, fmt.Errorf("ABI unpack error: %v", err)
+	}
+	return processed, nil
+}
+
+// CheckHealth reports whether the EVM node answers
+func (c *Client) CheckHealth(ctx context.Context) (string, error) {
+	head, err := c.client.BlockNumber(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get block number: %v", err)
+	}
+	return fmt.Sprintf("head block %d", head), nil
+}
+
+// CheckNetworkID fails if the RPC reports a different network than the route expects, so
+// a misconfigured URL cannot send payouts to the wrong chain
+func (c *Client) CheckNetworkID(ctx context.Context, expected uint64) error {
+	networkID, err := c.client.NetworkID(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get network ID: %v", err)
+	}
+	if !networkID.IsUint64() || networkID.Uint64() != expected {
+		return fmt.Errorf("RPC reports network ID %s, chain %d expects %d", networkID, c.chainID, expected)
+	}
+	return nil
+}
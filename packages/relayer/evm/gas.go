@@ -0,0 +1,74 @@
+package evm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+	"go.uber.org/zap"
+)
+
+// LikelyRevertError reports a transaction that was not broadcast because gas estimation
+// indicates it would revert
+type LikelyRevertError struct {
+	Reason string // Decoded revert reason from the estimation call
+}
+
+func (e *LikelyRevertError) Error() string {
+	if e.Reason == "" {
+		return "transaction would revert, not sent"
+	}
+	return fmt.Sprintf("transaction would revert, not sent: %s", e.Reason)
+}
+
+// estimateGasLimit estimates gas for calling to with data and applies the configured
+// multiplier, floor and ceiling
+func (c *Client) estimateGasLimit(ctx context.Context, to common.Address, data []byte) (uint64, error) {
+	estimate, err := c.client.EstimateGas(ctx, ethereum.CallMsg{
+		From: c.address,
+		To:   &to,
+		Data: data,
+	})
+	if err != nil {
+		if isRevertError(err) {
+			return 0, &LikelyRevertError{Reason: decodeRevertReason(err)}
+		}
+		return 0, fmt.Errorf("failed to estimate gas: %v", err)
+	}
+
+	limit := estimate
+	if c.opts.GasMultiplier > 0 {
+		limit = uint64(math.Ceil(float64(estimate) * c.opts.GasMultiplier))
+	}
+	if limit < c.opts.GasLimitFloor {
+		limit = c.opts.GasLimitFloor
+	}
+	if c.opts.GasLimitCeiling > 0 && limit > c.opts.GasLimitCeiling {
+		if estimate > c.opts.GasLimitCeiling {
+			return 0, fmt.Errorf("estimated gas %d exceeds the %d ceiling", estimate, c.opts.GasLimitCeiling)
+		}
+		limit = c.opts.GasLimitCeiling
+	}
+
+	c.logger.Debug("Estimated gas", zap.Uint64("estimate", estimate), zap.Uint64("gasLimit", limit))
+	return limit, nil
+}
+
+// isRevertError reports whether an eth_estimateGas/eth_call error came from EVM execution
+// rather than from the transport or the node
+func isRevertError(err error) bool {
+	var dataErr rpc.DataError
+	if errors.As(err, &dataErr) && dataErr.ErrorData() != nil {
+		return true
+	}
+
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "execution reverted") ||
+		strings.Contains(msg, "gas required exceeds allowance") ||
+		strings.Contains(msg, "always failing transaction")
+}
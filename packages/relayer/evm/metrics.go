@@ -0,0 +1,34 @@
+package evm
+
+import (
+	"math/big"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	evmGasUsedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "relayer",
+		Name:      "evm_gas_used_total",
+		Help:      "Gas used by mined relayer transactions, by chain",
+	}, []string{"chain"})
+
+	evmGasSpentWeiTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "relayer",
+		Name:      "evm_gas_spent_wei_total",
+		Help:      "Fees paid by mined relayer transactions, in wei, by chain",
+	}, []string{"chain"})
+
+	walletBalanceWei = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "relayer",
+		Name:      "wallet_balance_wei",
+		Help:      "Native balance of the relayer's EVM wallet, in wei, by chain",
+	}, []string{"chain"})
+)
+
+// weiToFloat converts a wei amount for a metric, accepting the precision loss
+func weiToFloat(wei *big.Int) float64 {
+	f, _ := new(big.Float).SetInt(wei).Float64()
+	return f
+}
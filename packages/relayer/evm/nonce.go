@@ -0,0 +1,157 @@
+package evm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"go.uber.org/zap"
+)
+
+// maxNonceAttempts bounds how often a send is retried after resyncing on a nonce conflict
+const maxNonceAttempts = 3
+
+// nonceGapGrace is how long the chain may lag behind our local nonce before the gap is
+// treated as left by dropped transactions and the local nonce is rewound
+var nonceGapGrace = time.Minute
+
+// nonceSource is the part of ethclient.Client the nonce manager needs
+type nonceSource interface {
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+}
+
+// NonceManager hands out nonces for one account locally. Sends are serialized so that
+// concurrent deliveries never share a nonce and reach the node in nonce order.
+type NonceManager struct {
+	mu       sync.Mutex
+	source   nonceSource
+	address  common.Address
+	next     uint64    // Next nonce to hand out
+	synced   bool      // Whether next has been loaded from the chain
+	gapSince time.Time // When the chain's pending nonce was first seen below next
+	logger   *zap.Logger
+	// onRewind, if set, is called with the new next nonce when the dropped nonces from it
+	// upwards are about to be reused
+	onRewind func(next uint64)
+}
+
+// NewNonceManager creates a nonce manager for address; the first send loads the nonce from the chain
+func NewNonceManager(source nonceSource, address common.Address, logger *zap.Logger) *NonceManager {
+	return &NonceManager{
+		source:  source,
+		address: address,
+		logger:  logger.With(zap.String("component", "NonceManager"), zap.String("address", address.Hex())),
+	}
+}
+
+// Send calls send with the next nonce and consumes the nonce only if the node accepted the
+// transaction. Nonce conflicts resync with the chain and retry with a fresh nonce.
+func (m *NonceManager) Send(ctx context.Context, send func(nonce uint64) error) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.sync(ctx); err != nil {
+		return err
+	}
+
+	for attempt := 1; ; attempt++ {
+		nonce := m.next
+		err := send(nonce)
+		switch {
+		case err == nil, isAlreadyKnown(err):
+			// "already known" means this exact transaction is in the pool, so the nonce is used
+			m.next = nonce + 1
+			return nil
+		case isNonceConflict(err) && attempt < maxNonceAttempts:
+			m.logger.Warn("Nonce conflict, resyncing with chain",
+				zap.Uint64("nonce", nonce),
+				zap.Int("attempt", attempt),
+				zap.Error(err))
+			if err := m.resync(ctx); err != nil {
+				return err
+			}
+			if m.next <= nonce {
+				m.next = nonce + 1
+			}
+		default:
+			return err
+		}
+	}
+}
+
+// Resync reloads the next nonce from the chain's pending state
+func (m *NonceManager) Resync(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.resync(ctx)
+}
+
+func (m *NonceManager) resync(ctx context.Context) error {
+	pending, err := m.source.PendingNonceAt(ctx, m.address)
+	if err != nil {
+		return fmt.Errorf("failed to get nonce: %v", err)
+	}
+
+	m.logger.Debug("Nonce synced with chain", zap.Uint64("previous", m.next), zap.Uint64("pending", pending))
+	m.next = pending
+	m.synced = true
+	m.gapSince = time.Time{}
+	return nil
+}
+
+// sync loads the nonce on first use and afterwards reconciles the local nonce with the chain
+func (m *NonceManager) sync(ctx context.Context) error {
+	if !m.synced {
+		return m.resync(ctx)
+	}
+
+	pending, err := m.source.PendingNonceAt(ctx, m.address)
+	if err != nil {
+		// Keep using the local nonce; a real conflict will surface as a send error
+		m.logger.Debug("Failed to check pending nonce", zap.Error(err))
+		return nil
+	}
+
+	switch {
+	case pending > m.next:
+		// Something else sent from this account
+		m.logger.Warn("Pending nonce ahead of local nonce, skipping forward",
+			zap.Uint64("local", m.next),
+			zap.Uint64("pending", pending))
+		m.next = pending
+		m.gapSince = time.Time{}
+	case pending < m.next:
+		// Our transactions may simply not have propagated yet; only rewind once the gap persists
+		if m.gapSince.IsZero() {
+			m.gapSince = time.Now()
+		} else if time.Since(m.gapSince) > nonceGapGrace {
+			m.logger.Warn("Recovering nonce gap left by dropped transactions",
+				zap.Uint64("local", m.next),
+				zap.Uint64("pending", pending))
+			if m.onRewind != nil {
+				m.onRewind(pending)
+			}
+			m.next = pending
+			m.gapSince = time.Time{}
+		}
+	default:
+		m.gapSince = time.Time{}
+	}
+	return nil
+}
+
+// isNonceConflict reports node errors meaning the nonce we used is already taken
+func isNonceConflict(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "nonce too low") ||
+		strings.Contains(msg, "replacement transaction underpriced")
+}
+
+// isAlreadyKnown reports node errors meaning the transaction is already in the pool
+func isAlreadyKnown(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "already known") || strings.Contains(msg, "known transaction")
+}
@@ -0,0 +1,184 @@
+package evm
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"go.uber.org/zap"
+)
+
+// fakeNonceSource reports a settable pending nonce
+type fakeNonceSource struct {
+	mu      sync.Mutex
+	pending uint64
+}
+
+func (s *fakeNonceSource) set(pending uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pending = pending
+}
+
+func (s *fakeNonceSource) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.pending, nil
+}
+
+// sendNonce sends through m and returns the nonce it was given
+func sendNonce(t *testing.T, m *NonceManager) uint64 {
+	t.Helper()
+	var got uint64
+	if err := m.Send(context.Background(), func(nonce uint64) error {
+		got = nonce
+		return nil
+	}); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	return got
+}
+
+func TestNonceManagerRewindsPersistentGap(t *testing.T) {
+	defer func(grace time.Duration) { nonceGapGrace = grace }(nonceGapGrace)
+	nonceGapGrace = 50 * time.Millisecond
+
+	source := &fakeNonceSource{pending: 5}
+	m := NewNonceManager(source, common.Address{}, zap.NewNop())
+	var rewoundTo []uint64
+	m.onRewind = func(next uint64) { rewoundTo = append(rewoundTo, next) }
+
+	for want := uint64(5); want < 8; want++ {
+		if got := sendNonce(t, m); got != want {
+			t.Fatalf("nonce = %d, want %d", got, want)
+		}
+	}
+
+	// Nonces 6 and 7 were dropped; the gap is tolerated until it outlasts the grace period
+	source.set(6)
+	if got := sendNonce(t, m); got != 8 {
+		t.Fatalf("nonce = %d within the grace period, want 8", got)
+	}
+	time.Sleep(2 * nonceGapGrace)
+	if got := sendNonce(t, m); got != 6 {
+		t.Fatalf("nonce = %d after the grace period, want the rewound 6", got)
+	}
+	if len(rewoundTo) != 1 || rewoundTo[0] != 6 {
+		t.Errorf("onRewind calls = %v, want [6]", rewoundTo)
+	}
+}
+
+func TestNonceManagerFollowsExternalSends(t *testing.T) {
+	source := &fakeNonceSource{pending: 3}
+	m := NewNonceManager(source, common.Address{}, zap.NewNop())
+	m.onRewind = func(next uint64) { t.Errorf("onRewind(%d) called when the chain was ahead", next) }
+
+	sendNonce(t, m)
+	source.set(10)
+	if got := sendNonce(t, m); got != 10 {
+		t.Errorf("nonce = %d, want the chain's pending 10", got)
+	}
+}
+
+func TestNonceManagerResyncsOnConflict(t *testing.T) {
+	source := &fakeNonceSource{pending: 1}
+	m := NewNonceManager(source, common.Address{}, zap.NewNop())
+	sendNonce(t, m)
+
+	source.set(4)
+	var tried []uint64
+	err := m.Send(context.Background(), func(nonce uint64) error {
+		tried = append(tried, nonce)
+		if nonce < 5 {
+			source.set(5)
+			return errors.New("nonce too low")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if len(tried) != 2 || tried[0] != 4 || tried[1] != 5 {
+		t.Errorf("tried nonces %v, want [4 5]", tried)
+	}
+
+	failed := errors.New("insufficient funds")
+	if err := m.Send(context.Background(), func(uint64) error { return failed }); !errors.Is(err, failed) {
+		t.Errorf("Send = %v, want the send error", err)
+	}
+	if got := sendNonce(t, m); got != 6 {
+		t.Errorf("nonce = %d after a failed send, want 6 to be reused", got)
+	}
+}
+
+func TestNonceManagerConcurrentSends(t *testing.T) {
+	source := &fakeNonceSource{}
+	m := NewNonceManager(source, common.Address{}, zap.NewNop())
+
+	const senders = 20
+	var (
+		mu   sync.Mutex
+		used = make(map[uint64]bool)
+		wg   sync.WaitGroup
+	)
+	for i := 0; i < senders; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var nonce uint64
+			if err := m.Send(context.Background(), func(n uint64) error {
+				nonce = n
+				source.set(n + 1) // The node saw it
+				return nil
+			}); err != nil {
+				t.Errorf("Send: %v", err)
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if used[nonce] {
+				t.Errorf("nonce %d handed out twice", nonce)
+			}
+			used[nonce] = true
+		}()
+	}
+	wg.Wait()
+
+	for nonce := uint64(0); nonce < senders; nonce++ {
+		if !used[nonce] {
+			t.Errorf("nonce %d skipped", nonce)
+		}
+	}
+}
+
+func TestTxTrackerDropFrom(t *testing.T) {
+	tracker := newTxTracker()
+	to := common.HexToAddress("0x01")
+	txs := make([]*types.Transaction, 4)
+	for i := range txs {
+		txs[i] = types.NewTx(&types.DynamicFeeTx{Nonce: uint64(i), To: &to, GasFeeCap: big.NewInt(1), GasTipCap: big.NewInt(1)})
+		tracker.add(txs[i])
+	}
+	tracker.markMined(3)
+
+	tracker.dropFrom(2)
+	if _, ok := tracker.lookup(txs[1].Hash()); !ok {
+		t.Error("nonce below the rewind was dropped")
+	}
+	if _, ok := tracker.lookup(txs[2].Hash()); ok {
+		t.Error("dropped nonce is still tracked")
+	}
+	if _, ok := tracker.lookup(txs[3].Hash()); !ok {
+		t.Error("mined nonce was dropped")
+	}
+	for _, p := range tracker.pending() {
+		if p.nonce >= 2 {
+			t.Errorf("nonce %d is still pending and would be bumped", p.nonce)
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package evm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"go.uber.org/zap"
+)
+
+// receiptPollInterval is how often Client polls for receipts and new blocks
+const receiptPollInterval = 2 * time.Second
+
+// RevertError reports a mined transaction whose execution reverted
+type RevertError struct {
+	TxHash string // Hash of the reverted transaction
+	Reason string // Decoded revert reason, if the replayed call returned one
+}
+
+func (e *RevertError) Error() string {
+	if e.Reason == "" {
+		return fmt.Sprintf("transaction %s reverted", e.TxHash)
+	}
+	return fmt.Sprintf("transaction %s reverted: %s", e.TxHash, e.Reason)
+}
+
+// CancelledError reports a transaction that CancelTransaction replaced with a no-op. The
+// delivery was stopped on purpose, so it should not be retried.
+type CancelledError struct {
+	TxHash     string // Hash of the cancelled transaction
+	CancelHash string // Hash of the mined cancellation
+}
+
+func (e *CancelledError) Error() string {
+	return fmt.Sprintf("transaction %s was cancelled by %s", e.TxHash, e.CancelHash)
+}
+
+// WaitForReceipt blocks until txHash, or a fee-bumped replacement of it, is mined with the
+// configured number of confirmations. A reverted transaction is returned as a *RevertError
+// carrying the decoded reason, and a cancelled one as a *CancelledError.
+func (c *Client) WaitForReceipt(ctx context.Context, txHash string) (*types.Receipt, error) {
+	if c.opts.ReceiptTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.opts.ReceiptTimeout)
+		defer cancel()
+	}
+
+	hash := common.HexToHash(txHash)
+	ticker := time.NewTicker(receiptPollInterval)
+	defer ticker.Stop()
+
+	for {
+		receipt, err := c.minedVersion(ctx, hash)
+		if err != nil {
+			return nil, err
+		}
+		if receipt != nil {
+			c.observeGasSpent(receipt)
+			if cancelHash := c.txs.cancelledBy(hash); receipt.TxHash == cancelHash {
+				// A cancelled delivery was stopped on purpose; retrying would undo that
+				return receipt, &CancelledError{TxHash: txHash, CancelHash: cancelHash.Hex()}
+			}
+			if receipt.Status != types.ReceiptStatusSuccessful {
+				return receipt, &RevertError{TxHash: receipt.TxHash.Hex(), Reason: c.revertReason(ctx, receipt)}
+			}
+			c.logger.Debug("Transaction confirmed",
+				zap.String("txHash", txHash),
+				zap.Uint64("block", receipt.BlockNumber.Uint64()),
+				zap.Uint64("gasUsed", receipt.GasUsed))
+			return receipt, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("transaction %s not confirmed: %w", txHash, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// observeGasSpent adds the gas and fees of a mined transaction to the gas metrics
+func (c *Client) observeGasSpent(receipt *types.Receipt) {
+	evmGasUsedTotal.WithLabelValues(c.chainLabel()).Add(float64(receipt.GasUsed))
+	if receipt.EffectiveGasPrice != nil {
+		fee := new(big.Int).Mul(receipt.EffectiveGasPrice, new(big.Int).SetUint64(receipt.GasUsed))
+		evmGasSpentWeiTotal.WithLabelValues(c.chainLabel()).Add(weiToFloat(fee))
+	}
+}
+
+// minedVersion returns the confirmed receipt of whichever version of hash was mined
+func (c *Client) minedVersion(ctx context.Context, hash common.Hash) (*types.Receipt, error) {
+	for _, version := range c.txs.versions(hash) {
+		receipt, err := c.confirmedReceipt(ctx, version)
+		if err != nil || receipt != nil {
+			return receipt, err
+		}
+	}
+	return nil, nil
+}
+
+// confirmedReceipt returns the receipt once it is buried under enough blocks, or nil if it is not yet
+func (c *Client) confirmedReceipt(ctx context.Context, hash common.Hash) (*types.Receipt, error) {
+	receipt, err := c.client.TransactionReceipt(ctx, hash)
+	if errors.Is(err, ethereum.NotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		c.logger.Debug("Failed to fetch receipt, will retry", zap.String("txHash", hash.Hex()), zap.Error(err))
+		return nil, nil
+	}
+
+	if c.opts.Confirmations <= 1 {
+		return receipt, nil
+	}
+
+	head, err := c.client.BlockNumber(ctx)
+	if err != nil {
+		c.logger.Debug("Failed to fetch block number, will retry", zap.Error(err))
+		return nil, nil
+	}
+	if head+1 < receipt.BlockNumber.Uint64()+c.opts.Confirmations {
+		return nil, nil
+	}
+
+	// Re-read the receipt so a reorg that dropped the transaction is noticed. If it was mined
+	// again in another block, it has to collect its confirmations there on a later poll.
+	again, err := c.client.TransactionReceipt(ctx, hash)
+	if err != nil {
+		return nil, nil
+	}
+	if again.BlockHash != receipt.BlockHash {
+		c.logger.Info("Transaction moved by a reorg, waiting for confirmations again",
+			zap.String("txHash", hash.Hex()),
+			zap.Uint64("block", again.BlockNumber.Uint64()))
+		return nil, nil
+	}
+	return again, nil
+}
+
+// revertReason replays a reverted transaction as an eth_call against the parent block to recover its reason
+func (c *Client) revertReason(ctx context.Context, receipt *types.Receipt) string {
+	tx, _, err := c.client.TransactionByHash(ctx, receipt.TxHash)
+	if err != nil {
+		c.logger.Debug("Failed to fetch reverted transaction", zap.String("txHash", receipt.TxHash.Hex()), zap.Error(err))
+		return ""
+	}
+
+	msg := ethereum.CallMsg{
+		From:  c.address,
+		To:    tx.To(),
+		Gas:   tx.Gas(),
+		Value: tx.Value(),
+		Data:  tx.Data(),
+	}
+	parent := new(big.Int).Sub(receipt.BlockNumber, big.NewInt(1))
+
+	_, err = c.client.CallContract(ctx, msg, parent)
+	if err == nil {
+		// The call succeeds in isolation; the revert depended on ordering within the block or ran out of gas
+		if receipt.GasUsed >= tx.Gas() {
+			return "out of gas"
+		}
+		return ""
+	}
+	return decodeRevertReason(err)
+}
+
+// decodeRevertReason extracts a Solidity revert reason from an eth_call or eth_estimateGas error
+func decodeRevertReason(err error) string {
+	var dataErr rpc.DataError
+	if errors.As(err, &dataErr) {
+		if data, ok := dataErr.ErrorData().(string); ok {
+			if raw, decodeErr := hexutil.Decode(data); decodeErr == nil {
+				if reason, unpackErr := abi.UnpackRevert(raw); unpackErr == nil {
+					return reason
+				}
+			}
+		}
+	}
+
+	// Fall back to the node's message, e.g. "execution reverted: Message already processed"
+	return strings.TrimPrefix(err.Error(), "execution reverted: ")
+}
@@ -0,0 +1,90 @@
+package evm
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"go.uber.org/zap"
+)
+
+// fakeReceiptNode serves eth_blockNumber and eth_getTransactionReceipt from a scripted list of
+// receipts, one per lookup, repeating the last
+type fakeReceiptNode struct {
+	mu       sync.Mutex
+	head     uint64
+	receipts []*types.Receipt
+}
+
+func (n *fakeReceiptNode) BlockNumber() hexutil.Uint64 {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return hexutil.Uint64(n.head)
+}
+
+func (n *fakeReceiptNode) GetTransactionReceipt(hash common.Hash) *types.Receipt {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	receipt := n.receipts[0]
+	if len(n.receipts) > 1 {
+		n.receipts = n.receipts[1:]
+	}
+	return receipt
+}
+
+func minedReceipt(hash common.Hash, block uint64, blockHash string) *types.Receipt {
+	return &types.Receipt{
+		Status:      types.ReceiptStatusSuccessful,
+		TxHash:      hash,
+		BlockHash:   common.HexToHash(blockHash),
+		BlockNumber: new(big.Int).SetUint64(block),
+		Logs:        []*types.Log{},
+	}
+}
+
+func newReceiptClient(t *testing.T, node *fakeReceiptNode, confirmations uint64) *Client {
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", node); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(server.Stop)
+	return &Client{
+		client: ethclient.NewClient(rpc.DialInProc(server)),
+		txs:    newTxTracker(),
+		opts:   Options{Confirmations: confirmations},
+		logger: zap.NewNop(),
+	}
+}
+
+func TestConfirmedReceipt(t *testing.T) {
+	hash := common.HexToHash("0x01")
+	tests := []struct {
+		name     string
+		head     uint64
+		receipts []*types.Receipt
+		want     bool
+	}{
+		{"confirmed", 12, []*types.Receipt{minedReceipt(hash, 10, "0xa")}, true},
+		{"too few confirmations", 11, []*types.Receipt{minedReceipt(hash, 10, "0xa")}, false},
+		// Moved from block 10 to 12 by a reorg; it has one confirmation, not three
+		{"moved by a reorg", 12, []*types.Receipt{minedReceipt(hash, 10, "0xa"), minedReceipt(hash, 12, "0xb")}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newReceiptClient(t, &fakeReceiptNode{head: tt.head, receipts: tt.receipts}, 3)
+			receipt, err := c.confirmedReceipt(context.Background(), hash)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if (receipt != nil) != tt.want {
+				t.Errorf("confirmedReceipt = %v, want confirmed %v", receipt, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,207 @@
+package evm
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"go.uber.org/zap"
+)
+
+// remoteSignTimeout bounds each remote signing request
+const remoteSignTimeout = 30 * time.Second
+
+// Signer signs EVM transactions for one account
+type Signer interface {
+	// Address returns the account transactions are signed for
+	Address() common.Address
+	// SignTx returns tx signed for chainID
+	SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+}
+
+// KeySigner signs with a private key held in memory
+type KeySigner struct {
+	key     *ecdsa.PrivateKey
+	address common.Address
+}
+
+// NewKeySigner creates a signer for key
+func NewKeySigner(key *ecdsa.PrivateKey) *KeySigner {
+	return &KeySigner{key: key, address: crypto.PubkeyToAddress(key.PublicKey)}
+}
+
+// NewKeystoreSigner decrypts a geth keystore file with the password in passwordFile
+func NewKeystoreSigner(keystoreFile, passwordFile string) (*KeySigner, error) {
+	keyJSON, err := os.ReadFile(keystoreFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keystore file: %v", err)
+	}
+	password, err := os.ReadFile(passwordFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keystore password file: %v", err)
+	}
+
+	key, err := keystore.DecryptKey(keyJSON, strings.TrimRight(string(password), "\r\n"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt keystore %s: %v", keystoreFile, err)
+	}
+	return NewKeySigner(key.PrivateKey), nil
+}
+
+// Address returns the account of the key
+func (s *KeySigner) Address() common.Address {
+	return s.address
+}
+
+// SignTx signs tx with the key
+func (s *KeySigner) SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return types.SignTx(tx, types.LatestSignerForChainID(chainID), s.key)
+}
+
+// RemoteSigner asks an external signer, such as Clef or web3signer, to sign transactions
+// with eth_signTransaction, so the key never enters the relayer process
+type RemoteSigner struct {
+	client  *rpc.Client
+	address common.Address
+	logger  *zap.Logger
+}
+
+// NewRemoteSigner connects to the signer at url. If address is empty, the signer's first
+// account from eth_accounts is used.
+func NewRemoteSigner(ctx context.Context, url, address string, logger *zap.Logger) (*RemoteSigner, error) {
+	client, err := rpc.DialContext(ctx, url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to remote signer: %v", err)
+	}
+	s := &RemoteSigner{
+		client: client,
+		logger: logger.With(zap.String("component", "RemoteSigner")),
+	}
+
+	if address != "" {
+		s.address = common.HexToAddress(address)
+		return s, nil
+	}
+
+	var accounts []common.Address
+	if err := client.CallContext(ctx, &accounts, "eth_accounts"); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to list remote signer accounts: %v", err)
+	}
+	if len(accounts) == 0 {
+		client.Close()
+		return nil, fmt.Errorf("remote signer has no accounts")
+	}
+	s.address = accounts[0]
+	s.logger.Info("Using remote signer account", zap.String("address", s.address.Hex()))
+	return s, nil
+}
+
+// Close disconnects from the remote signer
+func (s *RemoteSigner) Close() {
+	s.client.Close()
+}
+
+// Address returns the account the remote signer signs for
+func (s *RemoteSigner) Address() common.Address {
+	return s.address
+}
+
+// signTxArgs are the eth_signTransaction parameters. Geth reads "input", web3signer "data".
+type signTxArgs struct {
+	From                 common.Address  `json:"from"`
+	To                   *common.Address `json:"to,omitempty"`
+	Gas                  hexutil.Uint64  `json:"gas"`
+	MaxFeePerGas         *hexutil.Big    `json:"maxFeePerGas"`
+	MaxPriorityFeePerGas *hexutil.Big    `json:"maxPriorityFeePerGas"`
+	Value                *hexutil.Big    `json:"value"`
+	Nonce                hexutil.Uint64  `json:"nonce"`
+	Input                hexutil.Bytes   `json:"input"`
+	Data                 hexutil.Bytes   `json:"data"`
+	ChainID              *hexutil.Big    `json:"chainId"`
+}
+
+// SignTx sends tx to the remote signer and checks that what comes back is the same
+// transaction, signed by the expected account
+func (s *RemoteSigner) SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	if tx.Type() != types.DynamicFeeTxType {
+		return nil, fmt.Errorf("remote signer only signs dynamic fee transactions, got type %d", tx.Type())
+	}
+	args := signTxArgs{
+		From:                 s.address,
+		To:                   tx.To(),
+		Gas:                  hexutil.Uint64(tx.Gas()),
+		MaxFeePerGas:         (*hexutil.Big)(tx.GasFeeCap()),
+		MaxPriorityFeePerGas: (*hexutil.Big)(tx.GasTipCap()),
+		Value:                (*hexutil.Big)(tx.Value()),
+		Nonce:                hexutil.Uint64(tx.Nonce()),
+		Input:                tx.Data(),
+		Data:                 tx.Data(),
+		ChainID:              (*hexutil.Big)(chainID),
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, remoteSignTimeout)
+	defer cancel()
+
+	var result json.RawMessage
+	if err := s.client.CallContext(ctx, &result, "eth_signTransaction", args); err != nil {
+		return nil, fmt.Errorf("remote signer: %v", err)
+	}
+	raw, err := decodeSignTxResult(result)
+	if err != nil {
+		return nil, err
+	}
+
+	signed := new(types.Transaction)
+	if err := signed.UnmarshalBinary(raw); err != nil {
+		return nil, fmt.Errorf("remote signer returned an invalid transaction: %v", err)
+	}
+	if err := checkSignedTx(tx, signed, chainID, s.address); err != nil {
+		return nil, fmt.Errorf("remote signer: %v", err)
+	}
+	return signed, nil
+}
+
+// decodeSignTxResult reads the raw signed transaction from an eth_signTransaction result,
+// which is either {"raw": "0x..", "tx": {..}} (geth, Clef) or the raw hex string (web3signer)
+func decodeSignTxResult(result json.RawMessage) ([]byte, error) {
+	var raw hexutil.Bytes
+	if err := json.Unmarshal(result, &raw); err == nil {
+		return raw, nil
+	}
+	var response struct {
+		Raw hexutil.Bytes `json:"raw"`
+	}
+	if err := json.Unmarshal(result, &response); err != nil || len(response.Raw) == 0 {
+		return nil, fmt.Errorf("unexpected eth_signTransaction result: %s", result)
+	}
+	return response.Raw, nil
+}
+
+// checkSignedTx verifies that signed is the unsigned transaction with a valid signature from
+// address, so a compromised signer cannot substitute a different transaction
+func checkSignedTx(unsigned, signed *types.Transaction, chainID *big.Int, address common.Address) error {
+	signer := types.LatestSignerForChainID(chainID)
+	sender, err := types.Sender(signer, signed)
+	if err != nil {
+		return fmt.Errorf("invalid signature: %v", err)
+	}
+	if sender != address {
+		return fmt.Errorf("transaction signed by %s, expected %s", sender.Hex(), address.Hex())
+	}
+	if signer.Hash(signed) != signer.Hash(unsigned) {
+		return fmt.Errorf("signed transaction does not match the request")
+	}
+	return nil
+}
@@ -0,0 +1,10 @@
+//go:build !pkcs11
+
+package evm
+
+import "fmt"
+
+// NewPKCS11Signer fails in builds without the pkcs11 tag, which need no cgo or PKCS#11 library
+func NewPKCS11Signer(modulePath, tokenLabel, keyLabel, pinFile string) (Signer, error) {
+	return nil, fmt.Errorf("PKCS#11 support is not compiled in; build with -tags pkcs11")
+}
@@ -0,0 +1,192 @@
+//go:build pkcs11
+
+package evm
+
+import (
+	"bytes"
+	"context"
+	"encoding/asn1"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/miekg/pkcs11"
+)
+
+// PKCS11Signer signs with a secp256k1 key that never leaves a PKCS#11 token, such as an
+// HSM or SoftHSM
+type PKCS11Signer struct {
+	mu      sync.Mutex // A PKCS#11 session runs one operation at a time
+	module  *pkcs11.Ctx
+	session pkcs11.SessionHandle
+	key     pkcs11.ObjectHandle
+	address common.Address
+}
+
+// NewPKCS11Signer loads modulePath, logs in to the token labelled tokenLabel with the PIN
+// in pinFile and finds the key pair labelled keyLabel
+func NewPKCS11Signer(modulePath, tokenLabel, keyLabel, pinFile string) (Signer, error) {
+	pin, err := os.ReadFile(pinFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read PKCS#11 PIN file: %v", err)
+	}
+
+	module := pkcs11.New(modulePath)
+	if module == nil {
+		return nil, fmt.Errorf("failed to load PKCS#11 module %s", modulePath)
+	}
+	if err := module.Initialize(); err != nil {
+		module.Destroy()
+		return nil, fmt.Errorf("failed to initialize PKCS#11 module: %v", err)
+	}
+
+	s := &PKCS11Signer{module: module}
+	if err := s.open(tokenLabel, keyLabel, strings.TrimRight(string(pin), "\r\n")); err != nil {
+		s.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *PKCS11Signer) open(tokenLabel, keyLabel, pin string) error {
+	slot, err := s.findSlot(tokenLabel)
+	if err != nil {
+		return err
+	}
+	s.session, err = s.module.OpenSession(slot, pkcs11.CKF_SERIAL_SESSION)
+	if err != nil {
+		return fmt.Errorf("failed to open PKCS#11 session: %v", err)
+	}
+	if err := s.module.Login(s.session, pkcs11.CKU_USER, pin); err != nil {
+		return fmt.Errorf("failed to log in to token %q: %v", tokenLabel, err)
+	}
+
+	if s.key, err = s.findObject(pkcs11.CKO_PRIVATE_KEY, keyLabel); err != nil {
+		return err
+	}
+	publicKey, err := s.findObject(pkcs11.CKO_PUBLIC_KEY, keyLabel)
+	if err != nil {
+		return err
+	}
+	attrs, err := s.module.GetAttributeValue(s.session, publicKey, []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_EC_POINT, nil),
+	})
+	if err != nil || len(attrs) == 0 {
+		return fmt.Errorf("failed to read public key %q: %v", keyLabel, err)
+	}
+
+	// CKA_EC_POINT is a DER OCTET STRING holding the uncompressed point
+	point := attrs[0].Value
+	var raw []byte
+	if rest, err := asn1.Unmarshal(point, &raw); err == nil && len(rest) == 0 {
+		point = raw
+	}
+	pub, err := crypto.UnmarshalPubkey(point)
+	if err != nil {
+		return fmt.Errorf("key %q is not a secp256k1 key: %v", keyLabel, err)
+	}
+	s.address = crypto.PubkeyToAddress(*pub)
+	return nil
+}
+
+func (s *PKCS11Signer) findSlot(tokenLabel string) (uint, error) {
+	slots, err := s.module.GetSlotList(true)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list PKCS#11 slots: %v", err)
+	}
+	for _, slot := range slots {
+		info, err := s.module.GetTokenInfo(slot)
+		if err == nil && info.Label == tokenLabel {
+			return slot, nil
+		}
+	}
+	return 0, fmt.Errorf("no PKCS#11 token labelled %q", tokenLabel)
+}
+
+func (s *PKCS11Signer) findObject(class uint, label string) (pkcs11.ObjectHandle, error) {
+	template := []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, class),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, label),
+	}
+	if err := s.module.FindObjectsInit(s.session, template); err != nil {
+		return 0, fmt.Errorf("failed to search for key %q: %v", label, err)
+	}
+	defer s.module.FindObjectsFinal(s.session)
+
+	objects, _, err := s.module.FindObjects(s.session, 2)
+	if err != nil {
+		return 0, fmt.Errorf("failed to search for key %q: %v", label, err)
+	}
+	if len(objects) != 1 {
+		return 0, fmt.Errorf("expected one key labelled %q, found %d", label, len(objects))
+	}
+	return objects[0], nil
+}
+
+// Address returns the account of the token key
+func (s *PKCS11Signer) Address() common.Address {
+	return s.address
+}
+
+// SignTx signs the transaction hash on the token
+func (s *PKCS11Signer) SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	signer := types.LatestSignerForChainID(chainID)
+	hash := signer.Hash(tx)
+
+	s.mu.Lock()
+	err := s.module.SignInit(s.session, []*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_ECDSA, nil)}, s.key)
+	var rs []byte
+	if err == nil {
+		rs, err = s.module.Sign(s.session, hash[:])
+	}
+	s.mu.Unlock()
+	if err != nil {
+		return nil, fmt.Errorf("PKCS#11 signing failed: %v", err)
+	}
+	if len(rs) != 64 {
+		return nil, fmt.Errorf("PKCS#11 returned a %d-byte signature, expected 64", len(rs))
+	}
+
+	sig, err := signatureFromRS(hash[:], new(big.Int).SetBytes(rs[:32]), new(big.Int).SetBytes(rs[32:]), s.address)
+	if err != nil {
+		return nil, err
+	}
+	return tx.WithSignature(signer, sig)
+}
+
+// Close logs out and unloads the module
+func (s *PKCS11Signer) Close() {
+	if s.session != 0 {
+		s.module.Logout(s.session)
+		s.module.CloseSession(s.session)
+	}
+	s.module.Finalize()
+	s.module.Destroy()
+}
+
+// signatureFromRS builds the 65-byte [R || S || V] signature for hash from a raw ECDSA
+// signature by address. Tokens return neither a low S nor a recovery ID, so S is
+// normalized to the lower half of the curve order and V is found by public key recovery.
+func signatureFromRS(hash []byte, r, s *big.Int, address common.Address) ([]byte, error) {
+	n := crypto.S256().Params().N
+	if s.Cmp(new(big.Int).Rsh(n, 1)) > 0 {
+		s = new(big.Int).Sub(n, s)
+	}
+
+	sig := make([]byte, crypto.SignatureLength)
+	r.FillBytes(sig[0:32])
+	s.FillBytes(sig[32:64])
+	for v := byte(0); v < 2; v++ {
+		sig[64] = v
+		pub, err := crypto.SigToPub(hash, sig)
+		if err == nil && bytes.Equal(crypto.PubkeyToAddress(*pub).Bytes(), address.Bytes()) {
+			return sig, nil
+		}
+	}
+	return nil, fmt.Errorf("signature does not recover to %s", address.Hex())
+}
@@ -0,0 +1,129 @@
+//go:build pkcs11
+
+package evm
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/miekg/pkcs11"
+)
+
+// secp256k1OID is the DER-encoded curve OID for CKA_EC_PARAMS
+var secp256k1OID = []byte{0x06, 0x05, 0x2b, 0x81, 0x04, 0x00, 0x0a}
+
+// TestPKCS11SignerSoftHSM signs a transaction with a key generated on a SoftHSM token.
+// Initialize a token first, for example:
+//
+//	softhsm2-util --init-token --free --label relayer-test --so-pin 0000 --pin 1234
+//	PKCS11_MODULE=/usr/lib/softhsm/libsofthsm2.so PKCS11_TOKEN_LABEL=relayer-test \
+//	PKCS11_PIN=1234 go test -tags pkcs11 -run PKCS11 .
+func TestPKCS11SignerSoftHSM(t *testing.T) {
+	modulePath := os.Getenv("PKCS11_MODULE")
+	tokenLabel := os.Getenv("PKCS11_TOKEN_LABEL")
+	pin := os.Getenv("PKCS11_PIN")
+	if modulePath == "" || tokenLabel == "" || pin == "" {
+		t.Skip("PKCS11_MODULE, PKCS11_TOKEN_LABEL and PKCS11_PIN are not set")
+	}
+
+	keyLabel := "relayer-test-" + randomHex(t, 4)
+	generateKeyPair(t, modulePath, tokenLabel, pin, keyLabel)
+
+	pinFile := filepath.Join(t.TempDir(), "pin")
+	if err := os.WriteFile(pinFile, []byte(pin+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	signer, err := NewPKCS11Signer(modulePath, tokenLabel, keyLabel, pinFile)
+	if err != nil {
+		t.Fatalf("NewPKCS11Signer: %v", err)
+	}
+	defer signer.(*PKCS11Signer).Close()
+
+	chainID := big.NewInt(421614)
+	to := common.HexToAddress("0x248EC2E5595480fF371031698ae3a4099b8dC229")
+	// Sign several transactions so both recovery IDs and high S values are likely exercised
+	for nonce := uint64(0); nonce < 8; nonce++ {
+		tx := types.NewTx(&types.DynamicFeeTx{
+			ChainID:   chainID,
+			Nonce:     nonce,
+			GasTipCap: big.NewInt(1e8),
+			GasFeeCap: big.NewInt(2e9),
+			Gas:       100000,
+			To:        &to,
+			Value:     big.NewInt(0),
+			Data:      []byte{0x01, 0x02},
+		})
+
+		signed, err := signer.SignTx(context.Background(), tx, chainID)
+		if err != nil {
+			t.Fatalf("SignTx(nonce %d): %v", nonce, err)
+		}
+		if err := checkSignedTx(tx, signed, chainID, signer.Address()); err != nil {
+			t.Fatalf("nonce %d: %v", nonce, err)
+		}
+	}
+}
+
+func generateKeyPair(t *testing.T, modulePath, tokenLabel, pin, keyLabel string) {
+	t.Helper()
+
+	module := pkcs11.New(modulePath)
+	if module == nil {
+		t.Fatalf("failed to load %s", modulePath)
+	}
+	if err := module.Initialize(); err != nil {
+		t.Fatal(err)
+	}
+	defer module.Destroy()
+	defer module.Finalize()
+
+	s := &PKCS11Signer{module: module}
+	slot, err := s.findSlot(tokenLabel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	session, err := module.OpenSession(slot, pkcs11.CKF_SERIAL_SESSION|pkcs11.CKF_RW_SESSION)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer module.CloseSession(session)
+	if err := module.Login(session, pkcs11.CKU_USER, pin); err != nil {
+		t.Fatal(err)
+	}
+	defer module.Logout(session)
+
+	_, _, err = module.GenerateKeyPair(session,
+		[]*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_EC_KEY_PAIR_GEN, nil)},
+		[]*pkcs11.Attribute{
+			pkcs11.NewAttribute(pkcs11.CKA_TOKEN, true),
+			pkcs11.NewAttribute(pkcs11.CKA_VERIFY, true),
+			pkcs11.NewAttribute(pkcs11.CKA_EC_PARAMS, secp256k1OID),
+			pkcs11.NewAttribute(pkcs11.CKA_LABEL, keyLabel),
+		},
+		[]*pkcs11.Attribute{
+			pkcs11.NewAttribute(pkcs11.CKA_TOKEN, true),
+			pkcs11.NewAttribute(pkcs11.CKA_SIGN, true),
+			pkcs11.NewAttribute(pkcs11.CKA_SENSITIVE, true),
+			pkcs11.NewAttribute(pkcs11.CKA_EXTRACTABLE, false),
+			pkcs11.NewAttribute(pkcs11.CKA_LABEL, keyLabel),
+		})
+	if err != nil {
+		t.Fatalf("GenerateKeyPair: %v", err)
+	}
+}
+
+func randomHex(t *testing.T, n int) string {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		t.Fatal(err)
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,177 @@
+package evm
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"go.uber.org/zap"
+)
+
+// fakeRemoteSigner serves eth_signTransaction, signing with key after tamper changes the
+// requested transaction, and returns the result in the form respond builds
+type fakeRemoteSigner struct {
+	key     *ecdsa.PrivateKey
+	tamper  func(tx *types.DynamicFeeTx)
+	respond func(raw []byte) json.RawMessage
+}
+
+func (s *fakeRemoteSigner) SignTransaction(args signTxArgs) (json.RawMessage, error) {
+	tx := &types.DynamicFeeTx{
+		ChainID:   args.ChainID.ToInt(),
+		Nonce:     uint64(args.Nonce),
+		GasTipCap: args.MaxPriorityFeePerGas.ToInt(),
+		GasFeeCap: args.MaxFeePerGas.ToInt(),
+		Gas:       uint64(args.Gas),
+		To:        args.To,
+		Value:     args.Value.ToInt(),
+		Data:      args.Input,
+	}
+	if s.tamper != nil {
+		s.tamper(tx)
+	}
+	signed, err := types.SignNewTx(s.key, types.LatestSignerForChainID(tx.ChainID), tx)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := signed.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return s.respond(raw), nil
+}
+
+// gethResult is the {"raw", "tx"} form of an eth_signTransaction result from geth and Clef
+func gethResult(raw []byte) json.RawMessage {
+	return json.RawMessage(fmt.Sprintf(`{"raw": %q, "tx": {}}`, hexutil.Encode(raw)))
+}
+
+// hexResult is the raw hex string form of an eth_signTransaction result from web3signer
+func hexResult(raw []byte) json.RawMessage {
+	return json.RawMessage(fmt.Sprintf("%q", hexutil.Encode(raw)))
+}
+
+func mustKey(t *testing.T) *ecdsa.PrivateKey {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func TestRemoteSignerChecksSignedTx(t *testing.T) {
+	key := mustKey(t)
+	chainID := big.NewInt(421614)
+	to := common.HexToAddress("0x248EC2E5595480fF371031698ae3a4099b8dC229")
+	unsigned := types.NewTx(&types.DynamicFeeTx{
+		ChainID:   chainID,
+		Nonce:     7,
+		GasTipCap: big.NewInt(1e8),
+		GasFeeCap: big.NewInt(2e9),
+		Gas:       100000,
+		To:        &to,
+		Value:     big.NewInt(0),
+		Data:      []byte{0x01, 0x02},
+	})
+
+	tests := []struct {
+		name    string
+		signer  *fakeRemoteSigner
+		wantErr string // Empty when the signed transaction is accepted
+	}{
+		{"geth result", &fakeRemoteSigner{key: key, respond: gethResult}, ""},
+		{"raw hex result", &fakeRemoteSigner{key: key, respond: hexResult}, ""},
+		{"signed by another account", &fakeRemoteSigner{key: mustKey(t), respond: gethResult}, "transaction signed by"},
+		{
+			"different nonce",
+			&fakeRemoteSigner{key: key, tamper: func(tx *types.DynamicFeeTx) { tx.Nonce++ }, respond: gethResult},
+			"does not match the request",
+		},
+		{
+			"different recipient",
+			&fakeRemoteSigner{key: key, tamper: func(tx *types.DynamicFeeTx) { tx.To = &common.Address{} }, respond: gethResult},
+			"does not match the request",
+		},
+		{
+			"different chain ID",
+			&fakeRemoteSigner{key: key, tamper: func(tx *types.DynamicFeeTx) { tx.ChainID = big.NewInt(1) }, respond: gethResult},
+			"invalid signature",
+		},
+		{
+			"malformed result",
+			&fakeRemoteSigner{key: key, respond: func([]byte) json.RawMessage { return json.RawMessage(`{"signed": true}`) }},
+			"unexpected eth_signTransaction result",
+		},
+		{
+			"result is not a transaction",
+			&fakeRemoteSigner{key: key, respond: func([]byte) json.RawMessage { return hexResult([]byte{0x12, 0x34}) }},
+			"invalid transaction",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := rpc.NewServer()
+			if err := server.RegisterName("eth", tt.signer); err != nil {
+				t.Fatal(err)
+			}
+			defer server.Stop()
+			s := &RemoteSigner{
+				client:  rpc.DialInProc(server),
+				address: crypto.PubkeyToAddress(key.PublicKey),
+				logger:  zap.NewNop(),
+			}
+			defer s.Close()
+
+			signed, err := s.SignTx(context.Background(), unsigned, chainID)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("SignTx: %v", err)
+				}
+				if signed.Hash() == unsigned.Hash() {
+					t.Error("SignTx returned the unsigned transaction")
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("SignTx = %v, want an error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDecodeSignTxResult(t *testing.T) {
+	tests := []struct {
+		name    string
+		result  string
+		want    []byte
+		wantErr bool
+	}{
+		{"raw hex", `"0x0102"`, []byte{1, 2}, false},
+		{"geth object", `{"raw": "0x0102", "tx": {"nonce": "0x7"}}`, []byte{1, 2}, false},
+		{"object without raw", `{"tx": {"nonce": "0x7"}}`, nil, true},
+		{"empty raw", `{"raw": "0x"}`, nil, true},
+		{"invalid hex", `"0xzz"`, nil, true},
+		{"number", `7`, nil, true},
+		{"null", `null`, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeSignTxResult(json.RawMessage(tt.result))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("decodeSignTxResult(%s) error = %v, want error %v", tt.result, err, tt.wantErr)
+			}
+			if string(got) != string(tt.want) {
+				t.Errorf("decodeSignTxResult(%s) = %x, want %x", tt.result, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,362 @@
+package evm
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"go.uber.org/zap"
+)
+
+const (
+	// txMonitorInterval is how often pending transactions are checked for being stuck
+	txMonitorInterval = 15 * time.Second
+	// MinFeeBumpPercent is the smallest bump nodes accept for a same-nonce replacement
+	MinFeeBumpPercent = 10
+	// txRetention is how long mined transactions stay tracked for receipt lookups
+	txRetention = time.Hour
+	// cancelGasLimit is the gas limit of the zero-value self-transfer used to cancel a transaction
+	cancelGasLimit = 21000
+)
+
+// pendingTx is every version broadcast for one nonce
+type pendingTx struct {
+	nonce      uint64
+	latest     *types.Transaction // Most recently broadcast version
+	hashes     []common.Hash      // All versions, oldest first
+	sentAt     time.Time          // When latest was broadcast
+	cancelHash common.Hash        // Set once the nonce has been taken over by a cancellation
+	mined      bool
+	minedAt    time.Time
+}
+
+// txTracker indexes the transactions Client has broadcast so replacements can be followed
+type txTracker struct {
+	mu      sync.Mutex
+	byNonce map[uint64]*pendingTx
+	byHash  map[common.Hash]*pendingTx
+}
+
+func newTxTracker() *txTracker {
+	return &txTracker{
+		byNonce: make(map[uint64]*pendingTx),
+		byHash:  make(map[common.Hash]*pendingTx),
+	}
+}
+
+// add records a newly broadcast transaction. A nonce that is tracked but not mined counts as
+// the same transaction only if the calldata and recipient match; otherwise the nonce was
+// reused after a rewind and the earlier transaction will never be mined.
+func (t *txTracker) add(tx *types.Transaction) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	p, ok := t.byNonce[tx.Nonce()]
+	if !ok || p.mined || !sameCall(p.latest, tx) {
+		if ok && !p.mined {
+			t.forget(p)
+		}
+		p = &pendingTx{nonce: tx.Nonce()}
+		t.byNonce[tx.Nonce()] = p
+	}
+	t.addVersion(p, tx)
+}
+
+// addReplacement records a fee-bumped or cancelling version of a tracked nonce
+func (t *txTracker) addReplacement(tx *types.Transaction) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	p, ok := t.byNonce[tx.Nonce()]
+	if !ok {
+		p = &pendingTx{nonce: tx.Nonce()}
+		t.byNonce[tx.Nonce()] = p
+	}
+	t.addVersion(p, tx)
+}
+
+func (t *txTracker) addVersion(p *pendingTx, tx *types.Transaction) {
+	p.latest = tx
+	p.hashes = append(p.hashes, tx.Hash())
+	p.sentAt = time.Now()
+	t.byHash[tx.Hash()] = p
+}
+
+// dropFrom forgets the unmined transactions with a nonce of at least next, which the chain
+// dropped, so they are neither followed for receipts nor bumped once the nonces are reused
+func (t *txTracker) dropFrom(next uint64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for nonce, p := range t.byNonce {
+		if nonce >= next && !p.mined {
+			t.forget(p)
+		}
+	}
+}
+
+// forget removes every version of p. Callers hold mu.
+func (t *txTracker) forget(p *pendingTx) {
+	for _, h := range p.hashes {
+		delete(t.byHash, h)
+	}
+	delete(t.byNonce, p.nonce)
+}
+
+// sameCall reports whether two transactions call the same recipient with the same calldata
+func sameCall(a, b *types.Transaction) bool {
+	if a.To() == nil || b.To() == nil {
+		return a.To() == b.To() && bytes.Equal(a.Data(), b.Data())
+	}
+	return *a.To() == *b.To() && bytes.Equal(a.Data(), b.Data())
+}
+
+// versions returns every hash broadcast for the same nonce as hash, or just hash if untracked
+func (t *txTracker) versions(hash common.Hash) []common.Hash {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	p, ok := t.byHash[hash]
+	if !ok {
+		return []common.Hash{hash}
+	}
+	return append([]common.Hash(nil), p.hashes...)
+}
+
+// cancelledBy returns the cancellation hash for the nonce of hash, if it was cancelled
+func (t *txTracker) cancelledBy(hash common.Hash) common.Hash {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if p, ok := t.byHash[hash]; ok {
+		return p.cancelHash
+	}
+	return common.Hash{}
+}
+
+// lookup returns a snapshot of the tracked nonce of hash
+func (t *txTracker) lookup(hash common.Hash) (pendingTx, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	p, ok := t.byHash[hash]
+	if !ok {
+		return pendingTx{}, false
+	}
+	return *p, true
+}
+
+// pending returns snapshots of the nonces that have not been mined yet
+func (t *txTracker) pending() []pendingTx {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var out []pendingTx
+	for _, p := range t.byNonce {
+		if !p.mined {
+			out = append(out, *p)
+		}
+	}
+	return out
+}
+
+func (t *txTracker) markMined(nonce uint64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if p, ok := t.byNonce[nonce]; ok {
+		p.mined = true
+		p.minedAt = time.Now()
+	}
+}
+
+func (t *txTracker) markCancelled(nonce uint64, cancelHash common.Hash) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if p, ok := t.byNonce[nonce]; ok {
+		p.cancelHash = cancelHash
+	}
+}
+
+// prune forgets transactions that were mined longer than txRetention ago
+func (t *txTracker) prune() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, p := range t.byNonce {
+		if p.mined && time.Since(p.minedAt) > txRetention {
+			t.forget(p)
+		}
+	}
+}
+
+// MonitorTransactions re-broadcasts transactions that stay pending longer than
+// Options.StuckAfter with bumped fees, until ctx is cancelled
+func (c *Client) MonitorTransactions(ctx context.Context) {
+	if c.opts.StuckAfter <= 0 {
+		c.logger.Info("Stuck transaction monitor disabled")
+		return
+	}
+
+	ticker := time.NewTicker(txMonitorInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		c.checkPendingTransactions(ctx)
+		c.txs.prune()
+	}
+}
+
+func (c *Client) checkPendingTransactions(ctx context.Context) {
+	pending := c.txs.pending()
+	if len(pending) == 0 {
+		return
+	}
+
+	// Any nonce below the account's mined nonce has been included in some version
+	minedNonce, err := c.client.NonceAt(ctx, c.address, nil)
+	if err != nil {
+		c.logger.Debug("Failed to get mined nonce", zap.Error(err))
+		return
+	}
+
+	for _, p := range pending {
+		if p.nonce < minedNonce {
+			c.txs.markMined(p.nonce)
+			continue
+		}
+		if time.Since(p.sentAt) < c.opts.StuckAfter {
+			continue
+		}
+
+		c.logger.Warn("Transaction stuck, bumping fees",
+			zap.Uint64("nonce", p.nonce),
+			zap.String("txHash", p.latest.Hash().Hex()),
+			zap.Duration("pendingFor", time.Since(p.sentAt)))
+		if _, err := c.replaceTransaction(ctx, p.latest, false); err != nil {
+			c.logger.Error("Failed to bump stuck transaction",
+				zap.Uint64("nonce", p.nonce),
+				zap.Error(err))
+		}
+	}
+}
+
+// CancelTransaction replaces a pending transaction with a zero-value self-transfer at the
+// same nonce, freeing the nonce for later transactions. It returns the cancellation hash.
+func (c *Client) CancelTransaction(ctx context.Context, txHash string) (string, error) {
+	p, ok := c.txs.lookup(common.HexToHash(txHash))
+	if !ok {
+		return "", fmt.Errorf("transaction %s is not tracked", txHash)
+	}
+	if p.mined {
+		return "", fmt.Errorf("transaction %s is already mined", txHash)
+	}
+
+	tx, err := c.replaceTransaction(ctx, p.latest, true)
+	if err != nil {
+		return "", err
+	}
+	c.txs.markCancelled(tx.Nonce(), tx.Hash())
+	return tx.Hash().Hex(), nil
+}
+
+// replaceTransaction re-signs old at the same nonce with bumped fees, optionally turning
+// it into a cancellation
+func (c *Client) replaceTransaction(ctx context.Context, old *types.Transaction, cancel bool) (*types.Transaction, error) {
+	header, err := c.client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest block header: %v", err)
+	}
+
+	tipCap, feeCap, err := c.bumpedFees(old.GasTipCap(), old.GasFeeCap(), header.BaseFee)
+	if err != nil {
+		return nil, err
+	}
+
+	inner := &types.DynamicFeeTx{
+		ChainID:   old.ChainId(),
+		Nonce:     old.Nonce(),
+		GasTipCap: tipCap,
+		GasFeeCap: feeCap,
+		Gas:       old.Gas(),
+		To:        old.To(),
+		Value:     old.Value(),
+		Data:      old.Data(),
+	}
+	if cancel {
+		inner.Gas = cancelGasLimit
+		inner.To = &c.address
+		inner.Value = big.NewInt(0)
+		inner.Data = nil
+	}
+
+	signedTx, err := c.signer.SignTx(ctx, types.NewTx(inner), old.ChainId())
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign replacement transaction: %v", err)
+	}
+	if err := c.client.SendTransaction(ctx, signedTx); err != nil {
+		return nil, fmt.Errorf("failed to send replacement transaction: %v", err)
+	}
+
+	c.txs.addReplacement(signedTx)
+
+	c.logger.Info("Replaced pending transaction",
+		zap.Uint64("nonce", signedTx.Nonce()),
+		zap.Bool("cancel", cancel),
+		zap.String("oldTxHash", old.Hash().Hex()),
+		zap.String("newTxHash", signedTx.Hash().Hex()),
+		zap.String("gasTipCap", tipCap.String()),
+		zap.String("gasFeeCap", feeCap.String()))
+	return signedTx, nil
+}
+
+// bumpedFees raises both fee caps by FeeBumpPercent, keeps the fee cap above the current
+// base fee, and refuses to go beyond MaxFeePerGas
+func (c *Client) bumpedFees(oldTip, oldFeeCap, baseFee *big.Int) (*big.Int, *big.Int, error) {
+	percent := c.opts.FeeBumpPercent
+	if percent < MinFeeBumpPercent {
+		percent = MinFeeBumpPercent
+	}
+
+	tipCap := bumpByPercent(oldTip, percent)
+	feeCap := bumpByPercent(oldFeeCap, percent)
+
+	// Follow the market if the base fee moved more than our bump
+	if baseFee != nil {
+		floor := new(big.Int).Mul(baseFee, big.NewInt(2))
+		floor.Add(floor, tipCap)
+		if feeCap.Cmp(floor) < 0 {
+			feeCap = floor
+		}
+	}
+
+	if c.opts.MaxFeePerGas != nil && feeCap.Cmp(c.opts.MaxFeePerGas) > 0 {
+		feeCap = new(big.Int).Set(c.opts.MaxFeePerGas)
+		if feeCap.Cmp(bumpByPercent(oldFeeCap, MinFeeBumpPercent)) < 0 {
+			return nil, nil, fmt.Errorf("fee cap %s already at the %s ceiling", oldFeeCap, c.opts.MaxFeePerGas)
+		}
+	}
+	if tipCap.Cmp(feeCap) > 0 {
+		tipCap = new(big.Int).Set(feeCap)
+	}
+	return tipCap, feeCap, nil
+}
+
+// bumpByPercent returns value increased by percent, rounded up
+func bumpByPercent(value *big.Int, percent int) *big.Int {
+	bumped := new(big.Int).Mul(value, big.NewInt(int64(100+percent)))
+	bumped.Add(bumped, big.NewInt(99))
+	return bumped.Div(bumped, big.NewInt(100))
+}
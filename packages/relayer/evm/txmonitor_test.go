@@ -0,0 +1,109 @@
+package evm
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// verifyTx returns a transaction calling to with data at nonce, priced at feeCap
+func verifyTx(nonce uint64, to common.Address, data []byte, feeCap int64) *types.Transaction {
+	return types.NewTx(&types.DynamicFeeTx{
+		Nonce:     nonce,
+		To:        &to,
+		Data:      data,
+		GasTipCap: big.NewInt(1),
+		GasFeeCap: big.NewInt(feeCap),
+	})
+}
+
+func TestTxTrackerVersions(t *testing.T) {
+	treasury := common.HexToAddress("0x01")
+	first := verifyTx(7, treasury, []byte("vaa 1"), 10)
+	bumped := verifyTx(7, treasury, []byte("vaa 1"), 20)
+	resent := verifyTx(7, treasury, []byte("vaa 1"), 30)
+	cancel := verifyTx(7, common.HexToAddress("0x02"), nil, 40)
+
+	tracker := newTxTracker()
+	tracker.add(first)
+	tracker.addReplacement(bumped)
+	tracker.add(resent) // Same call at the same nonce, e.g. resent after a restart
+	tracker.addReplacement(cancel)
+
+	versions := tracker.versions(first.Hash())
+	if len(versions) != 4 || versions[3] != cancel.Hash() {
+		t.Errorf("versions of the first transaction = %v, want all four", versions)
+	}
+}
+
+func TestTxTrackerReusedNonceStartsNewTransaction(t *testing.T) {
+	treasury := common.HexToAddress("0x01")
+	dropped := verifyTx(7, treasury, []byte("vaa 1"), 10)
+	reused := verifyTx(7, treasury, []byte("vaa 2"), 10)
+
+	tracker := newTxTracker()
+	tracker.add(dropped)
+	tracker.markCancelled(7, common.HexToHash("0xc0"))
+	tracker.add(reused) // Nonce rewound and taken by another VAA's delivery
+
+	if versions := tracker.versions(reused.Hash()); len(versions) != 1 || versions[0] != reused.Hash() {
+		t.Errorf("versions of the new transaction = %v, want only itself", versions)
+	}
+	if versions := tracker.versions(dropped.Hash()); len(versions) != 1 || versions[0] != dropped.Hash() {
+		t.Errorf("versions of the dropped transaction = %v, want only itself", versions)
+	}
+	if cancelHash := tracker.cancelledBy(reused.Hash()); cancelHash != (common.Hash{}) {
+		t.Errorf("new transaction inherited cancellation %s", cancelHash.Hex())
+	}
+	if pending := tracker.pending(); len(pending) != 1 || pending[0].latest.Hash() != reused.Hash() {
+		t.Errorf("pending = %d transactions, want only the new one", len(pending))
+	}
+}
+
+func TestBumpedFees(t *testing.T) {
+	tests := []struct {
+		name        string
+		percent     int
+		maxFee      int64 // 0 leaves the fee cap unbounded
+		tip, feeCap int64
+		baseFee     int64 // 0 when the base fee is unknown
+		wantTip     int64
+		wantFeeCap  int64
+		wantErr     bool
+	}{
+		{name: "bump", percent: 25, tip: 10, feeCap: 100, wantTip: 13, wantFeeCap: 125},
+		{name: "below the replacement minimum", percent: 5, tip: 10, feeCap: 100, wantTip: 11, wantFeeCap: 110},
+		{name: "base fee moved further", percent: 25, tip: 10, feeCap: 100, baseFee: 100, wantTip: 13, wantFeeCap: 213},
+		{name: "capped at the ceiling", percent: 25, maxFee: 120, tip: 10, feeCap: 100, wantTip: 13, wantFeeCap: 120},
+		{name: "ceiling leaves less than the minimum", percent: 25, maxFee: 105, tip: 10, feeCap: 100, wantErr: true},
+		{name: "tip clamped to the fee cap", percent: 25, maxFee: 115, tip: 100, feeCap: 100, wantTip: 115, wantFeeCap: 115},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Client{opts: Options{FeeBumpPercent: tt.percent}}
+			if tt.maxFee != 0 {
+				c.opts.MaxFeePerGas = big.NewInt(tt.maxFee)
+			}
+			var baseFee *big.Int
+			if tt.baseFee != 0 {
+				baseFee = big.NewInt(tt.baseFee)
+			}
+
+			tip, feeCap, err := c.bumpedFees(big.NewInt(tt.tip), big.NewInt(tt.feeCap), baseFee)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("bumpedFees = %s, %s, want an error", tip, feeCap)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tip.Int64() != tt.wantTip || feeCap.Int64() != tt.wantFeeCap {
+				t.Errorf("bumpedFees = %s, %s, want %d, %d", tip, feeCap, tt.wantTip, tt.wantFeeCap)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+// Package payload encodes and decodes the Wormhole payload published by the Aztec MultiSig
+// contract when it executes a transfer proposal.
+//
+// The payload is the 32-byte source transaction ID followed by eight 31-byte fields, each the
+// low 31 bytes of a big-endian Noir Field:
+//
+//	[0:32]    source transaction ID
+//	[32:63]   field 0: token address (20 bytes, left-padded with zeros)
+//	[63:94]   field 1: target chain (2 bytes, little-endian), zero padding (9 bytes),
+//	          recipient address (20 bytes)
+//	[94:125]  field 2: amount (31-byte big-endian unsigned integer)
+//	[125:280] fields 3-7: reserved, zero
+//
+// Treasury.processPayload reads the same offsets on the EVM side and ignores the target chain,
+// which the relayer uses to pick the destination. A zero target chain means the default route.
+package payload
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	TxIDLength  = 32                                  // Source transaction ID prefix
+	FieldLength = 31                                  // Bytes per payload field
+	FieldCount  = 8                                   // Fields following the transaction ID
+	Length      = TxIDLength + FieldCount*FieldLength // Total payload length
+
+	tokenField     = 0
+	recipientField = 1
+	amountField    = 2
+
+	addressPadding = FieldLength - common.AddressLength
+	chainIDLength  = 2 // Target chain prefix of the recipient field
+)
+
+var (
+	// ErrInvalidLength is returned when the payload is not exactly Length bytes
+	ErrInvalidLength = errors.New("invalid payload length")
+	// ErrAmountOverflow is returned when an amount does not fit in a 31-byte field
+	ErrAmountOverflow = errors.New("amount does not fit in 31 bytes")
+)
+
+// maxAmount is the largest amount a 31-byte field can hold
+var maxAmount = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), FieldLength*8), big.NewInt(1))
+
+// TransferPayload is a token transfer approved by the MultiSig
+type TransferPayload struct {
+	TxID      [TxIDLength]byte // Source transaction ID
+	Token     common.Address   // ERC-20 token to transfer
+	Recipient common.Address   // Recipient of the tokens
+	Amount    *big.Int         // Amount in the token's base units
+	// Wormhole chain ID to pay out on, or 0 for the default destination
+	TargetChain uint16
+}
+
+// Decode parses a MultiSig transfer payload
+func Decode(data []byte) (*TransferPayload, error) {
+	if len(data) != Length {
+		return nil, fmt.Errorf("%w: got %d bytes, want %d", ErrInvalidLength, len(data), Length)
+	}
+
+	p := &TransferPayload{}
+	copy(p.TxID[:], data[:TxIDLength])
+
+	var err error
+	if p.Token, err = decodeAddress(field(data, tokenField)); err != nil {
+		return nil, fmt.Errorf("token: %w", err)
+	}
+	recipient := field(data, recipientField)
+	p.TargetChain = binary.LittleEndian.Uint16(recipient[:chainIDLength])
+	if p.Recipient, err = decodeAddress(recipient[chainIDLength:]); err != nil {
+		return nil, fmt.Errorf("recipient: %w", err)
+	}
+	p.Amount = new(big.Int).SetBytes(field(data, amountField))
+
+	return p, nil
+}
+
+// Encode serializes the payload in the layout the MultiSig contract publishes
+func (p *TransferPayload) Encode() ([]byte, error) {
+	amount := p.Amount
+	if amount == nil {
+		amount = new(big.Int)
+	}
+	if amount.Sign() < 0 || amount.Cmp(maxAmount) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrAmountOverflow, amount)
+	}
+
+	data := make([]byte, Length)
+	copy(data[:TxIDLength], p.TxID[:])
+	copy(field(data, tokenField)[addressPadding:], p.Token[:])
+	binary.LittleEndian.PutUint16(field(data, recipientField), p.TargetChain)
+	copy(field(data, recipientField)[addressPadding:], p.Recipient[:])
+	amount.FillBytes(field(data, amountField))
+
+	return data, nil
+}
+
+// String returns a compact description for logging
+func (p *TransferPayload) String() string {
+	return fmt.Sprintf("transfer %s of token %s to %s on chain %d (tx 0x%x)", p.Amount, p.Token.Hex(), p.Recipient.Hex(), p.TargetChain, p.TxID)
+}
+
+// field returns the bytes of field index i
+func field(data []byte, i int) []byte {
+	start := TxIDLength + i*FieldLength
+	return data[start : start+FieldLength]
+}
+
+// decodeAddress reads a 20-byte address from the end of f and checks that the bytes before it are zero
+func decodeAddress(f []byte) (common.Address, error) {
+	for _, b := range f[:len(f)-common.AddressLength] {
+		if b != 0 {
+			return common.Address{}, fmt.Errorf("address field has non-zero padding: 0x%x", f)
+		}
+	}
+	return common.BytesToAddress(f[len(f)-common.AddressLength:]), nil
+}
@@ -0,0 +1,104 @@
+package payload
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// fixture is a payload printed by the MultiSig contract's transfer_payload_fixtures test; see
+// packages/aztec-contracts/scripts/transfer_payload_fixtures.ts
+type fixture struct {
+	Name      string `json:"name"`
+	TxID      string `json:"txId"`
+	Token     string `json:"token"`
+	Recipient string `json:"recipient"`
+	Amount    string `json:"amount"`
+	Chain     uint16 `json:"targetChain"`
+	Payload   string `json:"payload"`
+}
+
+func loadFixtures(t *testing.T) []fixture {
+	t.Helper()
+
+	data, err := os.ReadFile("testdata/transfer_payloads.json")
+	if err != nil {
+		t.Fatalf("read fixtures: %v", err)
+	}
+	var fixtures []fixture
+	if err := json.Unmarshal(data, &fixtures); err != nil {
+		t.Fatalf("parse fixtures: %v", err)
+	}
+	return fixtures
+}
+
+func TestDecodeFixtures(t *testing.T) {
+	for _, fx := range loadFixtures(t) {
+		t.Run(fx.Name, func(t *testing.T) {
+			raw := hexutil.MustDecode(fx.Payload)
+
+			p, err := Decode(raw)
+			if err != nil {
+				t.Fatalf("Decode: %v", err)
+			}
+
+			if got := hexutil.Encode(p.TxID[:]); got != fx.TxID {
+				t.Errorf("TxID = %s, want %s", got, fx.TxID)
+			}
+			if p.Token != common.HexToAddress(fx.Token) {
+				t.Errorf("Token = %s, want %s", p.Token.Hex(), fx.Token)
+			}
+			if p.Recipient != common.HexToAddress(fx.Recipient) {
+				t.Errorf("Recipient = %s, want %s", p.Recipient.Hex(), fx.Recipient)
+			}
+			want, _ := new(big.Int).SetString(fx.Amount, 10)
+			if p.Amount.Cmp(want) != 0 {
+				t.Errorf("Amount = %s, want %s", p.Amount, want)
+			}
+			if p.TargetChain != fx.Chain {
+				t.Errorf("TargetChain = %d, want %d", p.TargetChain, fx.Chain)
+			}
+
+			encoded, err := p.Encode()
+			if err != nil {
+				t.Fatalf("Encode: %v", err)
+			}
+			if !bytes.Equal(encoded, raw) {
+				t.Errorf("round trip mismatch:\n got %x\nwant %x", encoded, raw)
+			}
+		})
+	}
+}
+
+func TestDecodeRejectsBadLength(t *testing.T) {
+	for _, n := range []int{0, TxIDLength, Length - 1, Length + 1} {
+		if _, err := Decode(make([]byte, n)); !errors.Is(err, ErrInvalidLength) {
+			t.Errorf("Decode(%d bytes) error = %v, want ErrInvalidLength", n, err)
+		}
+	}
+}
+
+func TestDecodeRejectsAddressPadding(t *testing.T) {
+	raw := hexutil.MustDecode(loadFixtures(t)[0].Payload)
+	raw[TxIDLength+FieldLength+chainIDLength] = 0x01 // first padding byte after the target chain
+
+	if _, err := Decode(raw); err == nil {
+		t.Fatal("Decode accepted a recipient with non-zero padding")
+	}
+}
+
+func TestEncodeRejectsAmountOverflow(t *testing.T) {
+	tooLarge := new(big.Int).Lsh(big.NewInt(1), FieldLength*8)
+	for _, amount := range []*big.Int{tooLarge, big.NewInt(-1)} {
+		p := &TransferPayload{Amount: amount}
+		if _, err := p.Encode(); !errors.Is(err, ErrAmountOverflow) {
+			t.Errorf("Encode(amount %s) error = %v, want ErrAmountOverflow", amount, err)
+		}
+	}
+}
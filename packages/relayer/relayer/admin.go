@@ -0,0 +1,398 @@
+package relayer
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	vaaLib "github.com/wormhole-foundation/wormhole/sdk/vaa"
+	"go.uber.org/zap"
+)
+
+// adminCancelTimeout bounds the RPC calls made to cancel a delivery transaction
+const adminCancelTimeout = 30 * time.Second
+
+// statusInFlight selects VAAs currently being processed in GET /admin/vaas
+const statusInFlight = "in_flight"
+
+// errRoutePaused is returned by the processor for VAAs held while their route is paused
+var errRoutePaused = errors.New("delivery paused")
+
+// adminVAA is a VAA record as returned by the admin API
+type adminVAA struct {
+	*VAARecord
+	RawBytes string `json:"rawBytes,omitempty"` // Hex, only when fetching a single VAA
+	Route    string `json:"route"`
+	InFlight bool   `json:"inFlight"`
+}
+
+// IsPaused reports whether delivery is paused on the named route
+func (r *Relayer) IsPaused(routeName string) bool {
+	r.pausedMu.RLock()
+	defer r.pausedMu.RUnlock()
+	return r.paused[routeName]
+}
+
+// PauseRoute stops delivery on the named route. VAAs that arrive meanwhile are recorded and
+// held until the route is resumed; deliveries already sending are not interrupted.
+func (r *Relayer) PauseRoute(routeName string) {
+	r.pausedMu.Lock()
+	r.paused[routeName] = true
+	r.pausedMu.Unlock()
+
+	r.logger.Warn("Delivery paused", zap.String("route", routeName))
+}
+
+// ResumeRoute restarts delivery on the named route and dispatches the VAAs held while it was
+// paused. They are queued in the background, so a full work queue does not block the caller.
+func (r *Relayer) ResumeRoute(routeName string) error {
+	r.pausedMu.Lock()
+	delete(r.paused, routeName)
+	r.pausedMu.Unlock()
+
+	r.logger.Info("Delivery resumed", zap.String("route", routeName))
+
+	held, err := r.store.List(VAAStatusReceived)
+	if err != nil {
+		return fmt.Errorf("failed to list held VAAs: %v", err)
+	}
+	for _, rec := range held {
+		if r.routeNameOf(rec) != routeName || !r.beginProcessingVAA(rec.Key) {
+			continue
+		}
+		r.dispatchInBackground(rec.RawBytes, rec.Key)
+	}
+	return nil
+}
+
+// registerAdminRoutes adds the admin API to mux, behind bearer token authentication
+func (r *Relayer) registerAdminRoutes(mux *http.ServeMux) {
+	routes := map[string]http.HandlerFunc{
+		"GET /admin/vaas":                                    r.handleListVAAs,
+		"POST /admin/vaas":                                   r.handleSubmitVAA,
+		"GET /admin/vaas/{key}":                              r.handleGetVAA,
+		"GET /admin/vaas/{chain}/{emitter}/{sequence}":       r.handleGetVAAByID,
+		"POST /admin/vaas/{key}/requeue":                     r.handleRequeueVAA,
+		"POST /admin/vaas/{key}/cancel":                      r.handleCancelVAA,
+		"POST /admin/vaas/{chain}/{emitter}/{sequence}/skip": r.handleSkipSequence,
+		"GET /admin/sequences":                               r.handleListSequences,
+		"GET /admin/routes":                                  r.handleListRoutes,
+		"POST /admin/routes/{route}/pause":                   r.handlePauseRoute,
+		"POST /admin/routes/{route}/resume":                  r.handleResumeRoute,
+	}
+	for pattern, handler := range routes {
+		mux.Handle(pattern, r.requireAdminToken(handler))
+	}
+}
+
+// requireAdminToken rejects requests without the configured bearer token
+func (r *Relayer) requireAdminToken(next http.Handler) http.Handler {
+	expected := []byte("Bearer " + r.config.AdminToken.Reveal())
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(req.Header.Get("Authorization")), expected) != 1 {
+			writeAdminError(w, http.StatusUnauthorized, errors.New("invalid or missing bearer token"))
+			return
+		}
+		next.ServeHTTP(w, req)
+	})
+}
+
+// handleListVAAs lists VAA records, optionally filtered by ?status=failed,dead_lettered,in_flight
+func (r *Relayer) handleListVAAs(w http.ResponseWriter, req *http.Request) {
+	var statuses []VAAStatus
+	inFlightOnly := false
+	if param := req.URL.Query().Get("status"); param != "" {
+		for _, status := range strings.Split(param, ",") {
+			if status == statusInFlight {
+				inFlightOnly = true
+				continue
+			}
+			statuses = append(statuses, VAAStatus(status))
+		}
+	}
+	if inFlightOnly && len(statuses) > 0 {
+		writeAdminError(w, http.StatusBadRequest, fmt.Errorf("%s cannot be combined with other statuses", statusInFlight))
+		return
+	}
+
+	records, err := r.store.List(statuses...)
+	if err != nil {
+		writeAdminError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	out := make([]adminVAA, 0, len(records))
+	for _, rec := range records {
+		view := r.adminView(rec)
+		if inFlightOnly && !view.InFlight {
+			continue
+		}
+		out = append(out, view)
+	}
+	writeAdminJSON(w, http.StatusOK, out)
+}
+
+// handleGetVAA returns one VAA record by its hash
+func (r *Relayer) handleGetVAA(w http.ResponseWriter, req *http.Request) {
+	rec, err := r.store.Get(req.PathValue("key"))
+	if err != nil {
+		writeAdminStoreError(w, err)
+		return
+	}
+	writeAdminJSON(w, http.StatusOK, r.adminDetail(rec))
+}
+
+// parseVAAID reads the {chain}/{emitter}/{sequence} path values of a request
+func parseVAAID(req *http.Request) (uint16, string, uint64, error) {
+	chain, err := strconv.ParseUint(req.PathValue("chain"), 10, 16)
+	if err != nil {
+		return 0, "", 0, fmt.Errorf("invalid chain: %v", err)
+	}
+	emitter, err := normalizeEmitter(req.PathValue("emitter"))
+	if err != nil {
+		return 0, "", 0, err
+	}
+	sequence, err := strconv.ParseUint(req.PathValue("sequence"), 10, 64)
+	if err != nil {
+		return 0, "", 0, fmt.Errorf("invalid sequence: %v", err)
+	}
+	return uint16(chain), emitter, sequence, nil
+}
+
+// handleGetVAAByID returns one VAA record by emitter chain, emitter address and sequence
+func (r *Relayer) handleGetVAAByID(w http.ResponseWriter, req *http.Request) {
+	chain, emitter, sequence, err := parseVAAID(req)
+	if err != nil {
+		writeAdminError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	records, err := r.store.ListEmitter(chain, emitter, sequence, sequence)
+	if err != nil {
+		writeAdminError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if len(records) == 0 {
+		writeAdminError(w, http.StatusNotFound, ErrVAANotFound)
+		return
+	}
+	writeAdminJSON(w, http.StatusOK, r.adminDetail(records[0]))
+}
+
+// handleSubmitVAA processes a raw VAA given as {"vaa": "<hex>"}, as if it came from the spy
+func (r *Relayer) handleSubmitVAA(w http.ResponseWriter, req *http.Request) {
+	var body struct {
+		VAA string `json:"vaa"`
+	}
+	if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+		writeAdminError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %v", err))
+		return
+	}
+
+	vaaBytes, err := hex.DecodeString(strings.TrimPrefix(body.VAA, "0x"))
+	if err != nil {
+		writeAdminError(w, http.StatusBadRequest, fmt.Errorf("invalid VAA hex: %v", err))
+		return
+	}
+	if _, err := vaaLib.Unmarshal(vaaBytes); err != nil {
+		writeAdminError(w, http.StatusBadRequest, fmt.Errorf("invalid VAA: %v", err))
+		return
+	}
+
+	key := computeVAAKey(vaaBytes)
+	if !r.beginProcessingVAA(key) {
+		writeAdminError(w, http.StatusConflict, fmt.Errorf("vaa %s is in flight, already delivered or dead-lettered", key))
+		return
+	}
+
+	if !r.tryDispatchVAA(vaaBytes, key) {
+		writeAdminError(w, http.StatusServiceUnavailable, errors.New("work queue is full, try again later"))
+		return
+	}
+	r.logger.Info("VAA submitted through admin API", zap.String("vaaHash", key))
+	writeAdminJSON(w, http.StatusAccepted, map[string]string{"key": key})
+}
+
+// handleRequeueVAA moves a dead-lettered VAA back into the retry queue
+func (r *Relayer) handleRequeueVAA(w http.ResponseWriter, req *http.Request) {
+	key := req.PathValue("key")
+	if err := r.RequeueVAA(key); err != nil {
+		if errors.Is(err, ErrVAANotFound) {
+			writeAdminError(w, http.StatusNotFound, err)
+			return
+		}
+		writeAdminError(w, http.StatusConflict, err)
+		return
+	}
+	writeAdminJSON(w, http.StatusOK, map[string]string{"key": key, "status": string(VAAStatusFailed)})
+}
+
+// handleCancelVAA replaces the pending delivery transaction of a VAA on an EVM route with a
+// zero-value self-transfer and dead-letters the VAA so it is not sent again
+func (r *Relayer) handleCancelVAA(w http.ResponseWriter, req *http.Request) {
+	key := req.PathValue("key")
+	rec, err := r.store.Get(key)
+	if err != nil {
+		writeAdminStoreError(w, err)
+		return
+	}
+	route, reason := r.matchRecordRoute(rec)
+	if reason != "" || route.Client != RouteClientEVM {
+		writeAdminError(w, http.StatusBadRequest, errors.New("only EVM deliveries can be cancelled"))
+		return
+	}
+	evmClient, err := r.evmClientFor(route)
+	if err != nil {
+		writeAdminError(w, http.StatusInternalServerError, err)
+		return
+	}
+	txHash := rec.PendingTxHash
+	if txHash == "" {
+		writeAdminError(w, http.StatusConflict, fmt.Errorf("vaa %s has no pending transaction (status %s)", key, rec.Status))
+		return
+	}
+
+	// Claim an idle VAA so the retry scheduler cannot start a delivery while it is cancelled.
+	// An in-flight delivery dead-letters itself once the cancellation is mined.
+	claimed := r.beginProcessingVAA(key)
+	if claimed {
+		defer r.releaseVAA(key)
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), adminCancelTimeout)
+	defer cancel()
+
+	cancelHash, err := evmClient.CancelTransaction(ctx, txHash)
+	if err != nil {
+		writeAdminError(w, http.StatusConflict, err)
+		return
+	}
+	r.logger.Warn("Delivery cancelled through admin API",
+		zap.String("vaaHash", key),
+		zap.String("txHash", txHash),
+		zap.String("cancelTxHash", cancelHash))
+
+	if claimed {
+		err := r.store.Update(key, func(rec *VAARecord) error {
+			if rec.Status != VAAStatusSubmitted && rec.Status != VAAStatusFailed || rec.PendingTxHash != txHash {
+				return fmt.Errorf("vaa %s changed while cancelling (status %s)", key, rec.Status)
+			}
+			rec.Status = VAAStatusDeadLettered
+			rec.LastError = fmt.Sprintf("transaction %s cancelled by operator", txHash)
+			rec.PendingTxHash = "" // A requeue sends a new transaction
+			rec.NextAttemptAt = time.Time{}
+			return nil
+		})
+		if err != nil {
+			writeAdminError(w, http.StatusConflict, err)
+			return
+		}
+		r.advanceSequence(rec.ChainID, rec.EmitterHex, rec.Sequence)
+	}
+	writeAdminJSON(w, http.StatusOK, map[string]string{"key": key, "cancelTxHash": cancelHash})
+}
+
+// handleSkipSequence moves an ordered emitter past the sequence it is waiting for
+func (r *Relayer) handleSkipSequence(w http.ResponseWriter, req *http.Request) {
+	chain, emitter, sequence, err := parseVAAID(req)
+	if err != nil {
+		writeAdminError(w, http.StatusBadRequest, err)
+		return
+	}
+	if err := r.SkipSequence(chain, emitter, sequence); err != nil {
+		writeAdminError(w, http.StatusConflict, err)
+		return
+	}
+	writeAdminJSON(w, http.StatusOK, map[string]uint64{"nextSequence": sequence + 1})
+}
+
+// handleListSequences reports the next sequence each ordered emitter is waiting for
+func (r *Relayer) handleListSequences(w http.ResponseWriter, req *http.Request) {
+	writeAdminJSON(w, http.StatusOK, r.NextSequences())
+}
+
+// handleListRoutes reports whether each route is paused
+func (r *Relayer) handleListRoutes(w http.ResponseWriter, req *http.Request) {
+	out := make(map[string]bool)
+	for _, name := range r.routeNames() {
+		out[name] = r.IsPaused(name)
+	}
+	writeAdminJSON(w, http.StatusOK, out)
+}
+
+func (r *Relayer) handlePauseRoute(w http.ResponseWriter, req *http.Request) {
+	route, ok := r.routeByName(req.PathValue("route"))
+	if !ok {
+		writeAdminError(w, http.StatusNotFound, fmt.Errorf("unknown route %q", req.PathValue("route")))
+		return
+	}
+	r.PauseRoute(route.Name)
+	writeAdminJSON(w, http.StatusOK, map[string]bool{"paused": true})
+}
+
+func (r *Relayer) handleResumeRoute(w http.ResponseWriter, req *http.Request) {
+	route, ok := r.routeByName(req.PathValue("route"))
+	if !ok {
+		writeAdminError(w, http.StatusNotFound, fmt.Errorf("unknown route %q", req.PathValue("route")))
+		return
+	}
+	if err := r.ResumeRoute(route.Name); err != nil {
+		writeAdminError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeAdminJSON(w, http.StatusOK, map[string]bool{"paused": false})
+}
+
+// isInFlight reports whether key is currently being processed
+// releaseVAA drops an admin claim on a VAA taken with beginProcessingVAA, leaving its record as is
+func (r *Relayer) releaseVAA(key string) {
+	r.dedupeMu.Lock()
+	defer r.dedupeMu.Unlock()
+	delete(r.inflightVAAs, key)
+}
+
+func (r *Relayer) isInFlight(key string) bool {
+	r.dedupeMu.Lock()
+	defer r.dedupeMu.Unlock()
+	_, ok := r.inflightVAAs[key]
+	return ok
+}
+
+func (r *Relayer) adminView(rec *VAARecord) adminVAA {
+	return adminVAA{
+		VAARecord: rec,
+		Route:     r.routeNameOf(rec),
+		InFlight:  r.isInFlight(rec.Key),
+	}
+}
+
+func (r *Relayer) adminDetail(rec *VAARecord) adminVAA {
+	view := r.adminView(rec)
+	view.RawBytes = hex.EncodeToString(rec.RawBytes)
+	return view
+}
+
+func writeAdminJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeAdminError(w http.ResponseWriter, status int, err error) {
+	writeAdminJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+func writeAdminStoreError(w http.ResponseWriter, err error) {
+	if errors.Is(err, ErrVAANotFound) {
+		writeAdminError(w, http.StatusNotFound, err)
+		return
+	}
+	writeAdminError(w, http.StatusInternalServerError, err)
+}
@@ -0,0 +1,278 @@
+package relayer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testAdminToken = "admin-token"
+
+// startAdminRun starts a fake run with the admin API enabled whose failed deliveries are not
+// retried within the test
+func startAdminRun(t *testing.T, configure func(*fileConfig)) *fakeRun {
+	return startFakeRun(t, func(fc *fileConfig) {
+		fc.Ops.AdminToken = testAdminToken
+		fc.Ops.AdminListenAddr = "127.0.0.1:0"
+		fc.Retry.InitialBackoff = time.Hour
+		if configure != nil {
+			configure(fc)
+		}
+	})
+}
+
+// admin serves one admin API request sent with the given Authorization header
+func (f *fakeRun) admin(method, path, authorization string) *httptest.ResponseRecorder {
+	mux := http.NewServeMux()
+	f.relayer.registerAdminRoutes(mux)
+
+	req := httptest.NewRequest(method, path, nil)
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	return w
+}
+
+// pendingDelivery publishes a transfer whose EVM transaction is never mined and waits until
+// its failed attempt is no longer in flight
+func (f *fakeRun) pendingDelivery() (string, []byte) {
+	f.e2eRun.t.Helper()
+
+	f.evm.FailReceipts(errors.New("transaction not confirmed: context deadline exceeded"))
+	_, transferBytes := f.transferVAA(e2eEVMChain)
+	f.source.Publish(transferBytes)
+	key := computeVAAKey(transferBytes)
+	waitFor(f.e2eRun.t, "a pending delivery", func() bool {
+		rec, err := f.relayer.store.Get(key)
+		return err == nil && rec.Status == VAAStatusFailed && rec.PendingTxHash != "" && !f.relayer.isInFlight(key)
+	})
+	return key, transferBytes
+}
+
+func TestAdminAPIRequiresToken(t *testing.T) {
+	f := startAdminRun(t, nil)
+
+	cases := []struct {
+		name          string
+		authorization string
+		wantCode      int
+	}{
+		{"no token", "", http.StatusUnauthorized},
+		{"wrong token", "Bearer wrong", http.StatusUnauthorized},
+		{"token without scheme", testAdminToken, http.StatusUnauthorized},
+		{"valid token", "Bearer " + testAdminToken, http.StatusOK},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if w := f.admin(http.MethodGet, "/admin/routes", tc.authorization); w.Code != tc.wantCode {
+				t.Errorf("GET /admin/routes returned %d, want %d", w.Code, tc.wantCode)
+			}
+		})
+	}
+}
+
+func TestAdminPauseAndResumeRoute(t *testing.T) {
+	f := startAdminRun(t, nil)
+	auth := "Bearer " + testAdminToken
+
+	for _, tc := range []struct {
+		path     string
+		wantCode int
+	}{
+		{"/admin/routes/unknown/pause", http.StatusNotFound},
+		{"/admin/routes/unknown/resume", http.StatusNotFound},
+		{"/admin/routes/aztec-to-evm/pause", http.StatusOK},
+	} {
+		if w := f.admin(http.MethodPost, tc.path, auth); w.Code != tc.wantCode {
+			t.Errorf("POST %s returned %d, want %d", tc.path, w.Code, tc.wantCode)
+		}
+	}
+
+	var paused map[string]bool
+	if err := json.NewDecoder(f.admin(http.MethodGet, "/admin/routes", auth).Body).Decode(&paused); err != nil {
+		t.Fatal(err)
+	}
+	if !paused["aztec-to-evm"] || paused["evm-to-aztec"] {
+		t.Errorf("paused routes = %v, want only aztec-to-evm", paused)
+	}
+
+	_, transferBytes := f.transferVAA(e2eEVMChain)
+	f.source.Publish(transferBytes)
+	f.waitForHeld(transferBytes)
+	if sent := f.evm.Sent(); len(sent) != 0 {
+		t.Fatalf("EVM submitter got %d VAAs on a paused route", len(sent))
+	}
+
+	if w := f.admin(http.MethodPost, "/admin/routes/aztec-to-evm/resume", auth); w.Code != http.StatusOK {
+		t.Fatalf("resume returned %d", w.Code)
+	}
+	f.waitForStatus(transferBytes, VAAStatusConfirmed)
+}
+
+func TestAdminCancelVAA(t *testing.T) {
+	cases := []struct {
+		name       string
+		setup      func(f *fakeRun) string // Returns the key of the VAA to cancel
+		wantCode   int
+		wantStatus VAAStatus // Status of the record after the request, if any
+		wantCancel bool
+	}{
+		{
+			name: "idle delivery is dead-lettered",
+			setup: func(f *fakeRun) string {
+				key, _ := f.pendingDelivery()
+				return key
+			},
+			wantCode:   http.StatusOK,
+			wantStatus: VAAStatusDeadLettered,
+			wantCancel: true,
+		},
+		{
+			name: "in-flight delivery is left to its worker",
+			setup: func(f *fakeRun) string {
+				key, _ := f.pendingDelivery()
+				if !f.relayer.beginProcessingVAA(key) {
+					f.e2eRun.t.Fatal("could not claim the pending delivery")
+				}
+				f.e2eRun.t.Cleanup(func() { f.relayer.releaseVAA(key) })
+				return key
+			},
+			wantCode:   http.StatusOK,
+			wantStatus: VAAStatusFailed,
+			wantCancel: true,
+		},
+		{
+			name: "confirmed delivery has no pending transaction",
+			setup: func(f *fakeRun) string {
+				_, transferBytes := f.transferVAA(e2eEVMChain)
+				f.source.Publish(transferBytes)
+				return f.waitForStatus(transferBytes, VAAStatusConfirmed).Key
+			},
+			wantCode:   http.StatusConflict,
+			wantStatus: VAAStatusConfirmed,
+		},
+		{
+			name: "Aztec delivery",
+			setup: func(f *fakeRun) string {
+				aztecBytes := f.aztecVAA()
+				f.source.Publish(aztecBytes)
+				return f.waitForStatus(aztecBytes, VAAStatusConfirmed).Key
+			},
+			wantCode:   http.StatusBadRequest,
+			wantStatus: VAAStatusConfirmed,
+		},
+		{
+			name:     "unknown VAA",
+			setup:    func(f *fakeRun) string { return strings.Repeat("00", 32) },
+			wantCode: http.StatusNotFound,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := startAdminRun(t, nil)
+			key := tc.setup(f)
+
+			w := f.admin(http.MethodPost, "/admin/vaas/"+key+"/cancel", "Bearer "+testAdminToken)
+			if w.Code != tc.wantCode {
+				t.Errorf("cancel returned %d, want %d: %s", w.Code, tc.wantCode, w.Body)
+			}
+			if cancelled := f.evm.Cancelled(); (len(cancelled) == 1) != tc.wantCancel {
+				t.Errorf("%d transactions cancelled, want a cancellation: %v", len(cancelled), tc.wantCancel)
+			}
+			if tc.wantStatus == "" {
+				return
+			}
+			rec, err := f.relayer.store.Get(key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if rec.Status != tc.wantStatus {
+				t.Errorf("record is %s after the cancel, want %s", rec.Status, tc.wantStatus)
+			}
+		})
+	}
+}
+
+func TestAdminSkipSequence(t *testing.T) {
+	f := startAdminRun(t, orderEVMToAztec)
+
+	first := f.aztecVAA()
+	f.source.Publish(first)
+	f.waitForStatus(first, VAAStatusConfirmed)
+
+	f.verifier.Fail(errors.New("prover down"))
+	f.aztec.FailSends(errors.New("PXE down"))
+	blocked, next := f.aztecVAA(), f.aztecVAA()
+	f.source.Publish(blocked)
+	rec := f.waitForStatus(blocked, VAAStatusFailed)
+	f.source.Publish(next)
+	f.waitForHeld(next)
+	f.verifier.Fail(nil)
+	f.aztec.FailSends(nil)
+
+	skip := func(chain string, sequence uint64) string {
+		return fmt.Sprintf("/admin/vaas/%s/%s/%d/skip", chain, rec.EmitterHex, sequence)
+	}
+	chain := fmt.Sprint(rec.ChainID)
+	for _, tc := range []struct {
+		name     string
+		path     string
+		wantCode int
+	}{
+		{"invalid chain", skip("aztec", rec.Sequence), http.StatusBadRequest},
+		{"sequence the emitter is not waiting for", skip(chain, rec.Sequence+1), http.StatusConflict},
+		{"blocked sequence", skip(chain, rec.Sequence), http.StatusOK},
+	} {
+		if w := f.admin(http.MethodPost, tc.path, "Bearer "+testAdminToken); w.Code != tc.wantCode {
+			t.Errorf("%s: skip returned %d, want %d: %s", tc.name, w.Code, tc.wantCode, w.Body)
+		}
+	}
+
+	f.waitForStatus(blocked, VAAStatusDeadLettered)
+	f.waitForStatus(next, VAAStatusConfirmed)
+}
+
+func TestAdminRequeueVAA(t *testing.T) {
+	f := startAdminRun(t, nil)
+	auth := "Bearer " + testAdminToken
+
+	// An operator cancels a stuck delivery, then redrives it once the chain recovers
+	cancelledKey, transferBytes := f.pendingDelivery()
+	if w := f.admin(http.MethodPost, "/admin/vaas/"+cancelledKey+"/cancel", auth); w.Code != http.StatusOK {
+		t.Fatalf("cancel returned %d: %s", w.Code, w.Body)
+	}
+	aztecBytes := f.aztecVAA()
+	f.source.Publish(aztecBytes)
+	confirmedKey := f.waitForStatus(aztecBytes, VAAStatusConfirmed).Key
+
+	for _, tc := range []struct {
+		name     string
+		key      string
+		wantCode int
+	}{
+		{"unknown VAA", strings.Repeat("00", 32), http.StatusNotFound},
+		{"confirmed VAA", confirmedKey, http.StatusConflict},
+		{"dead-lettered VAA", cancelledKey, http.StatusOK},
+		{"requeued VAA", cancelledKey, http.StatusConflict},
+	} {
+		if w := f.admin(http.MethodPost, "/admin/vaas/"+tc.key+"/requeue", auth); w.Code != tc.wantCode {
+			t.Errorf("%s: requeue returned %d, want %d: %s", tc.name, w.Code, tc.wantCode, w.Body)
+		}
+	}
+
+	f.evm.FailReceipts(nil)
+	rec := f.waitForStatus(transferBytes, VAAStatusConfirmed)
+	if sent := f.evm.Sent(); len(sent) != 2 {
+		t.Errorf("EVM submitter got %d transactions, want the redrive to replace the cancelled one", len(sent))
+	}
+	if rec.PendingTxHash != "" {
+		t.Errorf("redriven record still has pending transaction %s", rec.PendingTxHash)
+	}
+}
@@ -0,0 +1,237 @@
+package relayer
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	vaaLib "github.com/wormhole-foundation/wormhole/sdk/vaa"
+	"go.uber.org/zap"
+)
+
+// defaultBackfillMaxGap is used when BackfillOptions.MaxGap is not set
+const defaultBackfillMaxGap = 100
+
+// Backfill fetches are attempted backfillAttempts times, backfillRetryDelay apart, since the
+// API may index a VAA some time after the guardians sign it
+var (
+	backfillAttempts   = 3
+	backfillRetryDelay = 15 * time.Second
+	backfillTimeout    = 30 * time.Second
+)
+
+// BackfillOptions configures where VAAs missing from the spy stream are fetched from
+type BackfillOptions struct {
+	URL    string // Wormholescan-compatible API base URL ("" only reports gaps)
+	MaxGap int    // Most missing sequences fetched per detected gap
+}
+
+// sequenceTracker follows the highest contiguous sequence seen from one emitter
+type sequenceTracker struct {
+	contiguous uint64          // Every sequence up to this one has been seen
+	seen       map[uint64]bool // Sequences seen above contiguous
+	requested  map[uint64]bool // Missing sequences already reported or being backfilled
+}
+
+// observeSequence records a VAA of a route's emitter and backfills the sequences it skipped.
+// A VAA that would open a gap must carry valid guardian signatures first, so a forged sequence
+// cannot trigger fetches. A VAA that cannot be verified yet is left untracked; the gap is
+// checked again when its delivery is retried or the emitter's next VAA arrives.
+func (r *Relayer) observeSequence(ctx context.Context, route Route, vaaData *VAAData) {
+	if r.opensGap(vaaData.ChainID, vaaData.EmitterHex, vaaData.Sequence) && r.guardians != nil {
+		if err := verifyGuardianSignatures(ctx, r.guardians, vaaData.VAA); err != nil {
+			fields := []zap.Field{
+				zap.String("route", route.Name),
+				zap.String("emitter", emitterID(vaaData.ChainID, vaaData.EmitterHex)),
+				zap.Uint64("sequence", vaaData.Sequence),
+				zap.Error(err),
+			}
+			if isPermanent(err) {
+				r.logger.Warn("Ignoring sequence gap opened by a VAA that failed guardian verification", fields...)
+			} else {
+				r.logger.Warn("Could not verify VAA opening a sequence gap, checking the gap again later", fields...)
+			}
+			return
+		}
+	}
+
+	missing := r.trackSequence(vaaData.ChainID, vaaData.EmitterHex, vaaData.Sequence)
+	if len(missing) == 0 {
+		return
+	}
+	vaasMissedTotal.WithLabelValues(route.Name).Add(float64(len(missing)))
+	r.logger.Warn("Sequence gap detected",
+		zap.String("route", route.Name),
+		zap.String("emitter", emitterID(vaaData.ChainID, vaaData.EmitterHex)),
+		zap.Uint64("sequence", vaaData.Sequence),
+		zap.Uint64("firstMissing", missing[0]),
+		zap.Int("missing", len(missing)),
+		zap.Bool("backfill", r.backfill != nil))
+
+	if r.backfill != nil {
+		r.wg.Add(1)
+		go func() {
+			defer r.wg.Done()
+			r.backfillSequences(route, vaaData.ChainID, vaaData.EmitterHex, missing)
+		}()
+	}
+}
+
+// opensGap reports whether sequence skips sequences not seen yet from the emitter
+func (r *Relayer) opensGap(chainID uint16, emitterHex string, sequence uint64) bool {
+	r.gapsMu.Lock()
+	defer r.gapsMu.Unlock()
+	t := r.sequenceTracker(chainID, emitterHex, sequence)
+	return sequence > t.contiguous+1
+}
+
+// trackSequence marks sequence seen and returns the missing sequences below it that have not
+// been requested yet, at most MaxGap of them
+func (r *Relayer) trackSequence(chainID uint16, emitterHex string, sequence uint64) []uint64 {
+	r.gapsMu.Lock()
+	defer r.gapsMu.Unlock()
+
+	t := r.sequenceTracker(chainID, emitterHex, sequence)
+	if sequence <= t.contiguous {
+		return nil
+	}
+	t.seen[sequence] = true
+	delete(t.requested, sequence)
+
+	maxGap := r.config.Backfill.MaxGap
+	if maxGap <= 0 {
+		maxGap = defaultBackfillMaxGap
+	}
+	var missing []uint64
+	for s := t.contiguous + 1; s < sequence && len(missing) < maxGap; s++ {
+		if !t.seen[s] && !t.requested[s] {
+			missing = append(missing, s)
+			t.requested[s] = true
+		}
+	}
+
+	for t.seen[t.contiguous+1] {
+		t.contiguous++
+		delete(t.seen, t.contiguous)
+	}
+	return missing
+}
+
+// sequenceTracker returns the emitter's tracker, starting it at the highest stored sequence
+// below sequence, or just below sequence for an emitter the relayer never saw. Stored sequences
+// above it count as seen. Callers hold gapsMu.
+func (r *Relayer) sequenceTracker(chainID uint16, emitterHex string, sequence uint64) *sequenceTracker {
+	id := emitterID(chainID, emitterHex)
+	if t, ok := r.sequenceTrackers[id]; ok {
+		return t
+	}
+
+	t := &sequenceTracker{seen: make(map[uint64]bool), requested: make(map[uint64]bool)}
+	if sequence > 0 {
+		t.contiguous = sequence - 1
+	}
+	records, err := r.store.ListEmitter(chainID, emitterHex, 0, math.MaxUint64)
+	if err != nil {
+		r.logger.Error("Failed to load stored sequences, gaps before this VAA are not detected",
+			zap.String("emitter", id),
+			zap.Error(err))
+	}
+	found := false
+	for _, rec := range records {
+		if rec.Sequence == sequence {
+			continue
+		}
+		if rec.Sequence > sequence {
+			t.seen[rec.Sequence] = true
+			continue
+		}
+		if !found || rec.Sequence > t.contiguous {
+			t.contiguous, found = rec.Sequence, true
+		}
+	}
+	r.sequenceTrackers[id] = t
+	return t
+}
+
+// forgetRequested lets a later VAA report a sequence again after its backfill failed
+func (r *Relayer) forgetRequested(chainID uint16, emitterHex string, sequence uint64) {
+	r.gapsMu.Lock()
+	defer r.gapsMu.Unlock()
+	if t, ok := r.sequenceTrackers[emitterID(chainID, emitterHex)]; ok {
+		delete(t.requested, sequence)
+	}
+}
+
+// backfillSequences fetches the missing sequences and dispatches them like VAAs from the spy
+func (r *Relayer) backfillSequences(route Route, chainID uint16, emitterHex string, missing []uint64) {
+	ctx := r.dispatchCtx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	for _, sequence := range missing {
+		vaaBytes, err := r.fetchMissingVAA(ctx, chainID, emitterHex, sequence)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			vaasBackfilledTotal.WithLabelValues(route.Name, "failure").Inc()
+			r.logger.Error("Failed to backfill missing VAA",
+				zap.String("route", route.Name),
+				zap.String("emitter", emitterID(chainID, emitterHex)),
+				zap.Uint64("sequence", sequence),
+				zap.Error(err))
+			r.forgetRequested(chainID, emitterHex, sequence)
+			continue
+		}
+		vaasBackfilledTotal.WithLabelValues(route.Name, "success").Inc()
+
+		key := computeVAAKey(vaaBytes)
+		if !r.beginProcessingVAA(key) {
+			continue
+		}
+		r.logger.Info("Backfilling missing VAA",
+			zap.String("route", route.Name),
+			zap.String("vaaHash", key),
+			zap.Uint64("sequence", sequence))
+		r.dispatchVAA(ctx, vaaBytes, key)
+	}
+}
+
+// fetchMissingVAA fetches one sequence from the backfill source, retrying while it is not
+// available, and checks that the source returned the VAA asked for
+func (r *Relayer) fetchMissingVAA(ctx context.Context, chainID uint16, emitterHex string, sequence uint64) ([]byte, error) {
+	var err error
+	for attempt := 1; attempt <= backfillAttempts; attempt++ {
+		if attempt > 1 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(backfillRetryDelay):
+			}
+		}
+
+		var vaaBytes []byte
+		fetchCtx, cancel := context.WithTimeout(ctx, backfillTimeout)
+		vaaBytes, err = r.backfill.GetSignedVAA(fetchCtx, chainID, emitterHex, sequence)
+		cancel()
+		if err != nil {
+			r.logger.Debug("Backfill fetch failed",
+				zap.Uint64("sequence", sequence),
+				zap.Int("attempt", attempt),
+				zap.Error(err))
+			continue
+		}
+
+		v, parseErr := vaaLib.Unmarshal(vaaBytes)
+		if parseErr != nil {
+			return nil, fmt.Errorf("invalid VAA: %v", parseErr)
+		}
+		if uint16(v.EmitterChain) != chainID || v.EmitterAddress.String() != emitterHex || v.Sequence != sequence {
+			return nil, fmt.Errorf("got VAA %d/%s/%d", v.EmitterChain, v.EmitterAddress, v.Sequence)
+		}
+		return vaaBytes, nil
+	}
+	return nil, err
+}
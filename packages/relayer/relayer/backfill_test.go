@@ -0,0 +1,83 @@
+package relayer
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/wormhole-foundation/wormhole/aztec/relayer/relayertest"
+	vaaLib "github.com/wormhole-foundation/wormhole/sdk/vaa"
+)
+
+// flakyGuardianSets fails with err until it is cleared
+type flakyGuardianSets struct {
+	staticGuardianSets
+	mu  sync.Mutex
+	err error
+}
+
+func (p *flakyGuardianSets) fail(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.err = err
+}
+
+func (p *flakyGuardianSets) CurrentIndex(ctx context.Context) (uint32, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.err != nil {
+		return 0, p.err
+	}
+	return p.current, nil
+}
+
+func TestObserveSequenceVerifiesGapOpeners(t *testing.T) {
+	provider := &flakyGuardianSets{}
+	f := startFakeRun(t, nil, WithGuardianSetProvider(provider), WithVAAProcessor(func(*Relayer, *VAAData) error { return nil }))
+	provider.sets = map[uint32]*GuardianSet{0: {Keys: f.guardians.Addresses()}}
+	route, _ := f.relayer.routeByName("aztec-to-evm")
+
+	// observe passes the VAA with sequence through observeSequence, signed by the guardians or
+	// by unknown keys, and reports whether it was tracked
+	observe := func(sequence uint64, forged bool) bool {
+		v := f.guardians.NewVAA(e2eAztecChain, e2eAztecEmitter, sequence, nil)
+		var vaaBytes []byte
+		if forged {
+			vaaBytes = relayertest.SignWith(t, v, relayertest.NewKey(t), relayertest.NewKey(t), relayertest.NewKey(t))
+		} else {
+			vaaBytes = f.guardians.Sign(t, v)
+		}
+		parsed, err := vaaLib.Unmarshal(vaaBytes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.relayer.observeSequence(context.Background(), route, &VAAData{
+			VAA:        parsed,
+			ChainID:    e2eAztecChain,
+			EmitterHex: e2eAztecEmitter.String(),
+			Sequence:   sequence,
+		})
+		f.relayer.gapsMu.Lock()
+		defer f.relayer.gapsMu.Unlock()
+		tracker := f.relayer.sequenceTrackers[emitterID(e2eAztecChain, e2eAztecEmitter.String())]
+		return tracker != nil && (sequence <= tracker.contiguous || tracker.seen[sequence])
+	}
+
+	if !observe(1, false) {
+		t.Fatal("first VAA of the emitter was not tracked")
+	}
+	if observe(5, true) {
+		t.Error("forged VAA opened a gap")
+	}
+
+	// A transient verification failure leaves the gap to be checked again
+	provider.fail(errors.New("rpc unavailable"))
+	if observe(5, false) {
+		t.Error("VAA that could not be verified opened a gap")
+	}
+	provider.fail(nil)
+	if !observe(5, false) {
+		t.Error("VAA was not tracked once it could be verified")
+	}
+}
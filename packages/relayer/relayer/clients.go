@@ -0,0 +1,136 @@
+package relayer
+
+import (
+	"context"
+	"math/big"
+
+	spyv1 "github.com/certusone/wormhole/node/pkg/proto/spy/v1"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"go.uber.org/zap"
+)
+
+// VAASource streams signed VAAs to the relayer. spy.Client implements it over the Wormhole spy.
+type VAASource interface {
+	// SubscribeSignedVAA opens a stream of the VAAs matching the current filters
+	SubscribeSignedVAA(ctx context.Context) (spyv1.SpyRPCService_SubscribeSignedVAAClient, error)
+	// SetFilters replaces the emitter filters and ends the active stream
+	SetFilters(filters []*spyv1.FilterEntry)
+	// FiltersChanged reports, once, whether the last stream was ended by SetFilters
+	FiltersChanged() bool
+	// Close releases the connection
+	Close()
+}
+
+// EVMSubmitter delivers VAAs to a Treasury on one EVM chain. evm.Client implements it.
+type EVMSubmitter interface {
+	// GetAddress returns the account transactions are sent from
+	GetAddress() common.Address
+	// SendVerifyTransaction sends Treasury.verify(vaaBytes) and returns the transaction hash
+	SendVerifyTransaction(ctx context.Context, targetContract string, vaaBytes []byte) (string, error)
+	// WaitForReceipt waits for a sent transaction, or its replacement, to be confirmed
+	WaitForReceipt(ctx context.Context, txHash string) (*types.Receipt, error)
+	// IsMessageProcessed asks the Treasury whether a message was already delivered
+	IsMessageProcessed(ctx context.Context, targetContract string, emitterChain uint16, emitterAddress [32]byte, sequence uint64) (bool, error)
+	// CancelTransaction replaces a pending transaction with a no-op and returns its hash
+	CancelTransaction(ctx context.Context, txHash string) (string, error)
+	// MonitorTransactions replaces stuck transactions until ctx is cancelled
+	MonitorTransactions(ctx context.Context)
+	// Balance returns the native balance of the sending account
+	Balance(ctx context.Context) (*big.Int, error)
+	// CheckHealth reports whether the chain's node answers
+	CheckHealth(ctx context.Context) (string, error)
+}
+
+// AztecSubmitter delivers VAAs to a contract on Aztec. aztec.PXEClient implements it.
+type AztecSubmitter interface {
+	// SendVerifyTransaction calls verify_vaa on targetContract and returns the transaction hash
+	SendVerifyTransaction(ctx context.Context, targetContract string, vaaBytes []byte) (string, error)
+	// GetWalletAddress returns the account transactions are sent from
+	GetWalletAddress() string
+	// CheckHealth reports whether the PXE is reachable
+	CheckHealth(ctx context.Context) (string, error)
+}
+
+// VerificationService proves and submits VAAs to Aztec. verification.Client implements it.
+type VerificationService interface {
+	// VerifyVAA submits the VAA and returns the transaction hash
+	VerifyVAA(ctx context.Context, vaaBytes []byte) (string, error)
+	// CheckHealth fails if the service is unavailable
+	CheckHealth(ctx context.Context) error
+}
+
+// BackfillSource fetches signed VAAs the spy stream missed. wormholescan.Client implements it.
+type BackfillSource interface {
+	// GetSignedVAA returns the signed VAA with sequence from emitterHex on chainID
+	GetSignedVAA(ctx context.Context, chainID uint16, emitterHex string, sequence uint64) ([]byte, error)
+}
+
+// Option replaces a dependency New would otherwise create from the Config. The relayer
+// takes ownership of injected dependencies and closes them in Close.
+type Option func(*options)
+
+type options struct {
+	logger              *zap.Logger
+	vaaSource           VAASource
+	evmSubmitters       map[uint16]EVMSubmitter
+	aztecSubmitter      AztecSubmitter
+	verificationService VerificationService
+	backfillSource      BackfillSource
+	vaaStore            VAAStore
+	guardianSetProvider GuardianSetProvider
+	vaaProcessor        func(*Relayer, *VAAData) error
+}
+
+// WithLogger logs through logger instead of discarding log output
+func WithLogger(logger *zap.Logger) Option {
+	return func(o *options) { o.logger = logger }
+}
+
+// WithVAASource receives VAAs from source instead of dialing the spy
+func WithVAASource(source VAASource) Option {
+	return func(o *options) { o.vaaSource = source }
+}
+
+// WithEVMSubmitter delivers to the EVM chain with Wormhole chain ID chainID through submitter
+// instead of dialing the route's RPC URL
+func WithEVMSubmitter(chainID uint16, submitter EVMSubmitter) Option {
+	return func(o *options) {
+		if o.evmSubmitters == nil {
+			o.evmSubmitters = make(map[uint16]EVMSubmitter)
+		}
+		o.evmSubmitters[chainID] = submitter
+	}
+}
+
+// WithAztecSubmitter delivers to Aztec through submitter instead of dialing the PXE
+func WithAztecSubmitter(submitter AztecSubmitter) Option {
+	return func(o *options) { o.aztecSubmitter = submitter }
+}
+
+// WithVerificationService submits Aztec deliveries through service before falling back to
+// the Aztec submitter
+func WithVerificationService(service VerificationService) Option {
+	return func(o *options) { o.verificationService = service }
+}
+
+// WithBackfillSource fetches VAAs missing from the spy stream from source instead of
+// Config.Backfill.URL
+func WithBackfillSource(source BackfillSource) Option {
+	return func(o *options) { o.backfillSource = source }
+}
+
+// WithVAAStore keeps delivery state in store instead of the database at Config.StorePath
+func WithVAAStore(store VAAStore) Option {
+	return func(o *options) { o.vaaStore = store }
+}
+
+// WithGuardianSetProvider verifies VAAs against provider instead of Config.GuardianSetSource
+func WithGuardianSetProvider(provider GuardianSetProvider) Option {
+	return func(o *options) { o.guardianSetProvider = provider }
+}
+
+// WithVAAProcessor replaces the default delivery logic run for each VAA
+func WithVAAProcessor(processor func(*Relayer, *VAAData) error) Option {
+	return func(o *options) { o.vaaProcessor = processor }
+}
@@ -0,0 +1,161 @@
+package relayer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/wormhole-foundation/wormhole/aztec/relayer/aztec"
+	"github.com/wormhole-foundation/wormhole/aztec/relayer/evm"
+	"github.com/wormhole-foundation/wormhole/aztec/relayer/relayertest"
+	"github.com/wormhole-foundation/wormhole/aztec/relayer/spy"
+	"github.com/wormhole-foundation/wormhole/aztec/relayer/verification"
+	"github.com/wormhole-foundation/wormhole/aztec/relayer/wormholescan"
+)
+
+// The production clients and the relayertest fakes must satisfy the injectable interfaces
+var (
+	_ VAASource           = (*spy.Client)(nil)
+	_ VAASource           = (*relayertest.VAASource)(nil)
+	_ EVMSubmitter        = (*evm.Client)(nil)
+	_ EVMSubmitter        = (*relayertest.EVMSubmitter)(nil)
+	_ AztecSubmitter      = (*aztec.PXEClient)(nil)
+	_ AztecSubmitter      = (*relayertest.AztecSubmitter)(nil)
+	_ VerificationService = (*verification.Client)(nil)
+	_ VerificationService = (*relayertest.VerificationService)(nil)
+	_ BackfillSource      = (*wormholescan.Client)(nil)
+)
+
+// fakeRun is a relayer over in-memory fakes only, with nothing to dial
+type fakeRun struct {
+	*e2eRun
+	*e2eHarness
+	source   *relayertest.VAASource
+	evm      *relayertest.EVMSubmitter
+	aztec    *relayertest.AztecSubmitter
+	verifier *relayertest.VerificationService
+}
+
+// startFakeRun starts a relayer over fresh fakes; configure, if set, adjusts the config first
+func startFakeRun(t *testing.T, configure func(*fileConfig), opts ...Option) *fakeRun {
+	h := &e2eHarness{t: t, guardians: relayertest.NewGuardians(t, 0, 3)}
+
+	fc := defaultFileConfig()
+	fc.Routes = []routeJSON{{
+		Name:           "aztec-to-evm",
+		SourceChain:    e2eAztecChain,
+		Emitters:       []string{e2eAztecEmitter.String()},
+		DestChain:      e2eEVMChain,
+		TargetContract: relayertest.TreasuryAddress.Hex(),
+		Client:         RouteClientEVM,
+		RPCURL:         "http://127.0.0.1:0", // Never dialed
+	}, {
+		Name:           "evm-to-aztec",
+		SourceChain:    e2eEVMChain,
+		Emitters:       []string{e2eEVMEmitter.String()},
+		DestChain:      e2eAztecChain,
+		TargetContract: e2eAztecTarget,
+		Client:         RouteClientAztec,
+	}}
+	fc.Store.Path = ""
+	fc.Retry.InitialBackoff = 100 * time.Millisecond
+	fc.Retry.Jitter = 0
+	fc.Guardians.Source = "file"
+	fc.Guardians.File = h.guardians.WriteSetFile(t)
+	fc.Ops.ListenAddr = ""
+	fc.Health.CheckInterval = 0
+	if configure != nil {
+		configure(&fc)
+	}
+	config, err := fc.config()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f := &fakeRun{
+		e2eHarness: h,
+		source:     relayertest.NewVAASource(),
+		evm:        relayertest.NewEVMSubmitter(),
+		aztec:      relayertest.NewAztecSubmitter(),
+		verifier:   relayertest.NewVerificationService(),
+	}
+	relayer, err := New(config, append([]Option{
+		WithVAASource(f.source),
+		WithEVMSubmitter(e2eEVMChain, f.evm),
+		WithAztecSubmitter(f.aztec),
+		WithVerificationService(f.verifier),
+		WithVAAStore(NewMemoryVAAStore()),
+	}, opts...)...)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	f.e2eRun = &e2eRun{t: t, relayer: relayer, cancel: cancel, done: make(chan error, 1)}
+	go func() {
+		f.done <- relayer.Start(ctx)
+	}()
+	t.Cleanup(func() { f.stop() })
+	return f
+}
+
+func TestInjectedClientsDeliverOverEachRoute(t *testing.T) {
+	f := startFakeRun(t, nil)
+
+	_, transferBytes := f.transferVAA(e2eEVMChain)
+	aztecBytes := f.aztecVAA()
+	f.source.Publish(transferBytes)
+	f.source.Publish(aztecBytes)
+
+	f.waitForStatus(transferBytes, VAAStatusConfirmed)
+	f.waitForStatus(aztecBytes, VAAStatusConfirmed)
+	if sent := f.evm.Sent(); len(sent) != 1 || string(sent[0]) != string(transferBytes) {
+		t.Errorf("EVM submitter got %d VAAs, want the transfer", len(sent))
+	}
+	if verified := f.verifier.Verified(); len(verified) != 1 || string(verified[0]) != string(aztecBytes) {
+		t.Errorf("verification service got %d VAAs, want the Aztec VAA", len(verified))
+	}
+	if sent := f.aztec.Sent(); len(sent) != 0 {
+		t.Errorf("Aztec submitter got %d VAAs, want none while the verification service is up", len(sent))
+	}
+}
+
+func TestInjectedClientsFallBackAndResubscribe(t *testing.T) {
+	f := startFakeRun(t, nil)
+	f.verifier.Fail(errors.New("prover down"))
+
+	waitFor(t, "the first subscription", func() bool { return f.source.Streams() >= 1 })
+	f.source.Disconnect()
+	waitFor(t, "a resubscription", func() bool { return f.source.Streams() >= 2 })
+
+	aztecBytes := f.aztecVAA()
+	f.source.Publish(aztecBytes)
+	f.waitForStatus(aztecBytes, VAAStatusConfirmed)
+	if sent := f.aztec.Sent(); len(sent) != 1 {
+		t.Errorf("Aztec submitter got %d VAAs, want the fallback delivery", len(sent))
+	}
+}
+
+func TestInjectedClientsRetryWaitsForPendingEVMTransaction(t *testing.T) {
+	f := startFakeRun(t, func(fc *fileConfig) {
+		fc.Retry.InitialBackoff = 500 * time.Millisecond
+	})
+	f.evm.FailReceipts(errors.New("transaction not confirmed: context deadline exceeded"))
+
+	_, transferBytes := f.transferVAA(e2eEVMChain)
+	f.source.Publish(transferBytes)
+	rec := f.waitForStatus(transferBytes, VAAStatusFailed)
+	if rec.PendingTxHash == "" {
+		t.Fatal("failed delivery did not keep its pending transaction")
+	}
+
+	f.evm.FailReceipts(nil)
+	rec = f.waitForStatus(transferBytes, VAAStatusConfirmed)
+	if sent := f.evm.Sent(); len(sent) != 1 {
+		t.Errorf("EVM submitter got %d transactions, want the retry to wait for the first", len(sent))
+	}
+	if rec.PendingTxHash != "" || len(rec.TxHashes) != 1 {
+		t.Errorf("confirmed record has pending transaction %q and %d hashes, want none and 1", rec.PendingTxHash, len(rec.TxHashes))
+	}
+}
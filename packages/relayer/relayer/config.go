@@ -0,0 +1,565 @@
+package relayer
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/joho/godotenv"
+	"github.com/wormhole-foundation/wormhole/aztec/relayer/evm"
+	"gopkg.in/yaml.v3"
+)
+
+// fileConfig is the layout of the YAML config file selected with --config. Environment
+// variables override the file; see .env.template and relayer.example.yaml.
+type fileConfig struct {
+	Spy       spyFileConfig       `yaml:"spy"`
+	Chains    chainsFileConfig    `yaml:"chains"`
+	Emitters  emittersFileConfig  `yaml:"emitters"`
+	Arbitrum  arbitrumFileConfig  `yaml:"arbitrum"`
+	Aztec     aztecFileConfig     `yaml:"aztec"`
+	Routes    []routeJSON         `yaml:"routes"`
+	EVM       evmFileConfig       `yaml:"evm"`
+	Signer    signerFileConfig    `yaml:"signer"`
+	Store     storeFileConfig     `yaml:"store"`
+	Retry     retryFileConfig     `yaml:"retry"`
+	Workers   workersFileConfig   `yaml:"workers"`
+	Backfill  backfillFileConfig  `yaml:"backfill"`
+	Guardians guardiansFileConfig `yaml:"guardians"`
+	Ops       opsFileConfig       `yaml:"ops"`
+	Health    healthFileConfig    `yaml:"health"`
+}
+
+type spyFileConfig struct {
+	Host string `yaml:"host"` // SPY_RPC_HOST
+}
+
+// chainsFileConfig is the single Aztec/Arbitrum pair used when no routes are configured
+type chainsFileConfig struct {
+	Source uint16 `yaml:"source"` // SOURCE_CHAIN_ID
+	Dest   uint16 `yaml:"dest"`   // DEST_CHAIN_ID
+}
+
+type emittersFileConfig struct {
+	Aztec    []string `yaml:"aztec"`    // EMITTER_ADDRESS
+	Arbitrum []string `yaml:"arbitrum"` // ARBITRUM_EMITTER_ADDRESSES
+}
+
+type arbitrumFileConfig struct {
+	RPCURL         string `yaml:"rpcUrl"`         // ARBITRUM_RPC_URL
+	TargetContract string `yaml:"targetContract"` // ARBITRUM_TARGET_CONTRACT
+	NetworkID      uint64 `yaml:"networkId"`      // ARBITRUM_NETWORK_ID
+}
+
+type aztecFileConfig struct {
+	PXEURL                 string `yaml:"pxeUrl"`                 // AZTEC_PXE_URL
+	WalletAddress          string `yaml:"walletAddress"`          // AZTEC_WALLET_ADDRESS
+	TargetContract         string `yaml:"targetContract"`         // AZTEC_TARGET_CONTRACT
+	VerificationServiceURL string `yaml:"verificationServiceUrl"` // VERIFICATION_SERVICE_URL
+}
+
+type evmFileConfig struct {
+	Confirmations  uint64        `yaml:"confirmations"`  // EVM_CONFIRMATIONS
+	ReceiptTimeout time.Duration `yaml:"receiptTimeout"` // EVM_RECEIPT_TIMEOUT
+	StuckAfter     time.Duration `yaml:"stuckAfter"`     // EVM_STUCK_AFTER
+	FeeBumpPercent int           `yaml:"feeBumpPercent"` // EVM_FEE_BUMP_PERCENT
+	MaxFeeGwei     float64       `yaml:"maxFeeGwei"`     // EVM_MAX_FEE_GWEI
+	GasMultiplier  float64       `yaml:"gasMultiplier"`  // EVM_GAS_MULTIPLIER
+	GasLimitMin    uint64        `yaml:"gasLimitMin"`    // EVM_GAS_LIMIT_MIN
+	GasLimitMax    uint64        `yaml:"gasLimitMax"`    // EVM_GAS_LIMIT_MAX
+}
+
+type signerFileConfig struct {
+	Type          string           `yaml:"type"`          // EVM_SIGNER
+	PrivateKey    string           `yaml:"privateKey"`    // PRIVATE_KEY
+	KeystoreFile  string           `yaml:"keystoreFile"`  // EVM_KEYSTORE_FILE
+	PasswordFile  string           `yaml:"passwordFile"`  // EVM_KEYSTORE_PASSWORD_FILE
+	RemoteURL     string           `yaml:"remoteUrl"`     // EVM_REMOTE_SIGNER_URL
+	RemoteAddress string           `yaml:"remoteAddress"` // EVM_REMOTE_SIGNER_ADDRESS
+	PKCS11        pkcs11FileConfig `yaml:"pkcs11"`
+}
+
+type pkcs11FileConfig struct {
+	Module     string `yaml:"module"`     // EVM_PKCS11_MODULE
+	TokenLabel string `yaml:"tokenLabel"` // EVM_PKCS11_TOKEN_LABEL
+	KeyLabel   string `yaml:"keyLabel"`   // EVM_PKCS11_KEY_LABEL
+	PINFile    string `yaml:"pinFile"`    // EVM_PKCS11_PIN_FILE
+}
+
+type storeFileConfig struct {
+	Path      string        `yaml:"path"`      // STORE_PATH
+	Retention time.Duration `yaml:"retention"` // STORE_RETENTION
+}
+
+type retryFileConfig struct {
+	MaxAttempts    int           `yaml:"maxAttempts"`    // RETRY_MAX_ATTEMPTS
+	InitialBackoff time.Duration `yaml:"initialBackoff"` // RETRY_INITIAL_BACKOFF
+	MaxBackoff     time.Duration `yaml:"maxBackoff"`     // RETRY_MAX_BACKOFF
+	Multiplier     float64       `yaml:"multiplier"`     // RETRY_MULTIPLIER
+	Jitter         float64       `yaml:"jitter"`         // RETRY_JITTER
+}
+
+type workersFileConfig struct {
+	Count     int `yaml:"count"`     // WORKER_COUNT
+	QueueSize int `yaml:"queueSize"` // WORK_QUEUE_SIZE
+}
+
+type backfillFileConfig struct {
+	URL    string `yaml:"url"`    // BACKFILL_URL
+	MaxGap int    `yaml:"maxGap"` // BACKFILL_MAX_GAP
+}
+
+type guardiansFileConfig struct {
+	Source       string `yaml:"source"`       // GUARDIAN_SET_SOURCE
+	File         string `yaml:"file"`         // GUARDIAN_SET_FILE
+	CoreContract string `yaml:"coreContract"` // WORMHOLE_CORE_CONTRACT
+}
+
+type opsFileConfig struct {
+	ListenAddr      string `yaml:"listenAddr"`      // OPS_LISTEN_ADDR
+	AdminToken      string `yaml:"adminToken"`      // ADMIN_TOKEN
+	AdminListenAddr string `yaml:"adminListenAddr"` // ADMIN_LISTEN_ADDR
+}
+
+type healthFileConfig struct {
+	CheckInterval time.Duration `yaml:"checkInterval"` // HEALTH_CHECK_INTERVAL
+	SpyDownGrace  time.Duration `yaml:"spyDownGrace"`  // HEALTH_SPY_DOWN_GRACE
+	VAAStaleAfter time.Duration `yaml:"vaaStaleAfter"` // HEALTH_VAA_STALE_AFTER
+	MinBalanceETH float64       `yaml:"minBalanceEth"` // HEALTH_MIN_BALANCE_ETH
+}
+
+// defaultFileConfig holds the settings used when neither the file nor the environment sets them.
+// Contract addresses and the signing key have no defaults, so a missing one fails validation.
+func defaultFileConfig() fileConfig {
+	return fileConfig{
+		Spy:    spyFileConfig{Host: "localhost:7073"},
+		Chains: chainsFileConfig{Source: 56, Dest: 10003}, // Aztec, Arbitrum Sepolia
+		Arbitrum: arbitrumFileConfig{
+			RPCURL: "https://sepolia-rollup.arbitrum.io/rpc",
+		},
+		Aztec: aztecFileConfig{
+			PXEURL:                 "http://localhost:8090",
+			VerificationServiceURL: "http://localhost:8080",
+		},
+		EVM: evmFileConfig{
+			Confirmations:  1,
+			ReceiptTimeout: 5 * time.Minute,
+			StuckAfter:     90 * time.Second,
+			FeeBumpPercent: 25,
+			MaxFeeGwei:     5,
+			GasMultiplier:  1.3,
+			GasLimitMin:    100000,
+			GasLimitMax:    3000000,
+		},
+		Store: storeFileConfig{Retention: 7 * 24 * time.Hour},
+		Retry: retryFileConfig{
+			MaxAttempts:    8,
+			InitialBackoff: 15 * time.Second,
+			MaxBackoff:     30 * time.Minute,
+			Multiplier:     2,
+			Jitter:         0.2,
+		},
+		Workers:   workersFileConfig{Count: defaultWorkerCount, QueueSize: 256},
+		Backfill:  backfillFileConfig{MaxGap: defaultBackfillMaxGap},
+		Guardians: guardiansFileConfig{Source: "contract"},
+		Ops:       opsFileConfig{ListenAddr: ":9090", AdminListenAddr: "127.0.0.1:9091"},
+		Health: healthFileConfig{
+			CheckInterval: 30 * time.Second,
+			SpyDownGrace:  2 * time.Minute,
+			MinBalanceETH: 0.005,
+		},
+	}
+}
+
+// LoadConfig builds the Config from the defaults, the YAML file at path (if any), the .env
+// file and the environment, in increasing order of precedence, and validates it
+func LoadConfig(path string) (Config, error) {
+	if err := godotenv.Load(); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return Config{}, fmt.Errorf("failed to load .env file: %v", err)
+	}
+
+	fc := defaultFileConfig()
+	if path != "" {
+		if err := fc.load(path); err != nil {
+			return Config{}, err
+		}
+	}
+	if err := fc.applyEnv(); err != nil {
+		return Config{}, err
+	}
+
+	config, err := fc.config()
+	if err != nil {
+		return Config{}, err
+	}
+	if err := config.Validate(); err != nil {
+		return Config{}, fmt.Errorf("invalid configuration: %w", err)
+	}
+	return config, nil
+}
+
+// load decodes the YAML file at path over fc, rejecting unknown keys so typos are not ignored
+func (fc *fileConfig) load(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %v", err)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(fc); err != nil {
+		return fmt.Errorf("invalid config file %s: %v", path, err)
+	}
+	return nil
+}
+
+// applyEnv overrides fc with every environment variable that is set
+func (fc *fileConfig) applyEnv() error {
+	env := &envOverrides{}
+
+	env.setString("SPY_RPC_HOST", &fc.Spy.Host)
+	env.setUint16("SOURCE_CHAIN_ID", &fc.Chains.Source)
+	env.setUint16("DEST_CHAIN_ID", &fc.Chains.Dest)
+	env.setList("EMITTER_ADDRESS", &fc.Emitters.Aztec)
+	env.setList("ARBITRUM_EMITTER_ADDRESSES", &fc.Emitters.Arbitrum)
+	env.setString("ARBITRUM_RPC_URL", &fc.Arbitrum.RPCURL)
+	env.setString("ARBITRUM_TARGET_CONTRACT", &fc.Arbitrum.TargetContract)
+	env.setUint64("ARBITRUM_NETWORK_ID", &fc.Arbitrum.NetworkID)
+	env.setString("AZTEC_PXE_URL", &fc.Aztec.PXEURL)
+	env.setString("AZTEC_WALLET_ADDRESS", &fc.Aztec.WalletAddress)
+	env.setString("AZTEC_TARGET_CONTRACT", &fc.Aztec.TargetContract)
+	env.setString("VERIFICATION_SERVICE_URL", &fc.Aztec.VerificationServiceURL)
+	env.setRoutes("ROUTES", &fc.Routes)
+
+	env.setUint64("EVM_CONFIRMATIONS", &fc.EVM.Confirmations)
+	env.setDuration("EVM_RECEIPT_TIMEOUT", &fc.EVM.ReceiptTimeout)
+	env.setDuration("EVM_STUCK_AFTER", &fc.EVM.StuckAfter)
+	env.setInt("EVM_FEE_BUMP_PERCENT", &fc.EVM.FeeBumpPercent)
+	env.setFloat("EVM_MAX_FEE_GWEI", &fc.EVM.MaxFeeGwei)
+	env.setFloat("EVM_GAS_MULTIPLIER", &fc.EVM.GasMultiplier)
+	env.setUint64("EVM_GAS_LIMIT_MIN", &fc.EVM.GasLimitMin)
+	env.setUint64("EVM_GAS_LIMIT_MAX", &fc.EVM.GasLimitMax)
+
+	env.setString("EVM_SIGNER", &fc.Signer.Type)
+	env.setString("PRIVATE_KEY", &fc.Signer.PrivateKey)
+	env.setString("EVM_KEYSTORE_FILE", &fc.Signer.KeystoreFile)
+	env.setString("EVM_KEYSTORE_PASSWORD_FILE", &fc.Signer.PasswordFile)
+	env.setString("EVM_REMOTE_SIGNER_URL", &fc.Signer.RemoteURL)
+	env.setString("EVM_REMOTE_SIGNER_ADDRESS", &fc.Signer.RemoteAddress)
+	env.setString("EVM_PKCS11_MODULE", &fc.Signer.PKCS11.Module)
+	env.setString("EVM_PKCS11_TOKEN_LABEL", &fc.Signer.PKCS11.TokenLabel)
+	env.setString("EVM_PKCS11_KEY_LABEL", &fc.Signer.PKCS11.KeyLabel)
+	env.setString("EVM_PKCS11_PIN_FILE", &fc.Signer.PKCS11.PINFile)
+
+	env.setString("STORE_PATH", &fc.Store.Path)
+	env.setDuration("STORE_RETENTION", &fc.Store.Retention)
+	env.setInt("RETRY_MAX_ATTEMPTS", &fc.Retry.MaxAttempts)
+	env.setDuration("RETRY_INITIAL_BACKOFF", &fc.Retry.InitialBackoff)
+	env.setDuration("RETRY_MAX_BACKOFF", &fc.Retry.MaxBackoff)
+	env.setFloat("RETRY_MULTIPLIER", &fc.Retry.Multiplier)
+	env.setFloat("RETRY_JITTER", &fc.Retry.Jitter)
+	env.setInt("WORKER_COUNT", &fc.Workers.Count)
+	env.setInt("WORK_QUEUE_SIZE", &fc.Workers.QueueSize)
+	env.setString("BACKFILL_URL", &fc.Backfill.URL)
+	env.setInt("BACKFILL_MAX_GAP", &fc.Backfill.MaxGap)
+
+	env.setString("GUARDIAN_SET_SOURCE", &fc.Guardians.Source)
+	env.setString("GUARDIAN_SET_FILE", &fc.Guardians.File)
+	env.setString("WORMHOLE_CORE_CONTRACT", &fc.Guardians.CoreContract)
+
+	env.setString("OPS_LISTEN_ADDR", &fc.Ops.ListenAddr)
+	env.setString("ADMIN_TOKEN", &fc.Ops.AdminToken)
+	env.setString("ADMIN_LISTEN_ADDR", &fc.Ops.AdminListenAddr)
+	env.setDuration("HEALTH_CHECK_INTERVAL", &fc.Health.CheckInterval)
+	env.setDuration("HEALTH_SPY_DOWN_GRACE", &fc.Health.SpyDownGrace)
+	env.setDuration("HEALTH_VAA_STALE_AFTER", &fc.Health.VAAStaleAfter)
+	env.setFloat("HEALTH_MIN_BALANCE_ETH", &fc.Health.MinBalanceETH)
+
+	return errors.Join(env.errs...)
+}
+
+// config converts fc to a Config, parsing emitter lists and routes
+func (fc *fileConfig) config() (Config, error) {
+	aztecEmitters, err := ParseEmitterAllowlist(strings.Join(fc.Emitters.Aztec, ","))
+	if err != nil {
+		return Config{}, fmt.Errorf("aztec emitters: %v", err)
+	}
+	arbitrumEmitters, err := ParseEmitterAllowlist(strings.Join(fc.Emitters.Arbitrum, ","))
+	if err != nil {
+		return Config{}, fmt.Errorf("arbitrum emitters: %v", err)
+	}
+	routes, err := buildRoutes(fc.Routes)
+	if err != nil {
+		return Config{}, err
+	}
+
+	return Config{
+		SpyRPCHost:             fc.Spy.Host,
+		SourceChainID:          fc.Chains.Source,
+		DestChainID:            fc.Chains.Dest,
+		AztecEmitters:          aztecEmitters,
+		ArbitrumEmitters:       arbitrumEmitters,
+		Routes:                 routes,
+		ArbitrumRPCURL:         fc.Arbitrum.RPCURL,
+		ArbitrumTargetContract: fc.Arbitrum.TargetContract,
+		ArbitrumNetworkID:      fc.Arbitrum.NetworkID,
+		AztecWalletAddress:     fc.Aztec.WalletAddress,
+		AztecPXEURL:            fc.Aztec.PXEURL,
+		AztecTargetContract:    fc.Aztec.TargetContract,
+		VerificationServiceURL: fc.Aztec.VerificationServiceURL,
+		StorePath:              fc.Store.Path,
+		StoreRetention:         fc.Store.Retention,
+		RetryPolicy: RetryPolicy{
+			MaxAttempts:    fc.Retry.MaxAttempts,
+			InitialBackoff: fc.Retry.InitialBackoff,
+			MaxBackoff:     fc.Retry.MaxBackoff,
+			Multiplier:     fc.Retry.Multiplier,
+			Jitter:         fc.Retry.Jitter,
+		},
+		Workers: WorkerOptions{
+			Count:     fc.Workers.Count,
+			QueueSize: fc.Workers.QueueSize,
+		},
+		Backfill: BackfillOptions{
+			URL:    fc.Backfill.URL,
+			MaxGap: fc.Backfill.MaxGap,
+		},
+		EVM: evm.Options{
+			Confirmations:   fc.EVM.Confirmations,
+			ReceiptTimeout:  fc.EVM.ReceiptTimeout,
+			StuckAfter:      fc.EVM.StuckAfter,
+			FeeBumpPercent:  fc.EVM.FeeBumpPercent,
+			MaxFeePerGas:    gweiToWei(fc.EVM.MaxFeeGwei),
+			GasMultiplier:   fc.EVM.GasMultiplier,
+			GasLimitFloor:   fc.EVM.GasLimitMin,
+			GasLimitCeiling: fc.EVM.GasLimitMax,
+		},
+		Signer: SignerConfig{
+			Type:                 fc.Signer.Type,
+			PrivateKey:           Secret(fc.Signer.PrivateKey),
+			KeystoreFile:         fc.Signer.KeystoreFile,
+			KeystorePasswordFile: fc.Signer.PasswordFile,
+			RemoteURL:            fc.Signer.RemoteURL,
+			RemoteAddress:        fc.Signer.RemoteAddress,
+			PKCS11Module:         fc.Signer.PKCS11.Module,
+			PKCS11TokenLabel:     fc.Signer.PKCS11.TokenLabel,
+			PKCS11KeyLabel:       fc.Signer.PKCS11.KeyLabel,
+			PKCS11PINFile:        fc.Signer.PKCS11.PINFile,
+		},
+		GuardianSetSource:    fc.Guardians.Source,
+		GuardianSetFile:      fc.Guardians.File,
+		WormholeCoreContract: fc.Guardians.CoreContract,
+		OpsListenAddr:        fc.Ops.ListenAddr,
+		AdminToken:           Secret(fc.Ops.AdminToken),
+		AdminListenAddr:      fc.Ops.AdminListenAddr,
+		Health: HealthOptions{
+			CheckInterval: fc.Health.CheckInterval,
+			SpyDownGrace:  fc.Health.SpyDownGrace,
+			VAAStaleAfter: fc.Health.VAAStaleAfter,
+			MinBalance:    gweiToWei(fc.Health.MinBalanceETH * 1e9),
+		},
+	}, nil
+}
+
+// envOverrides copies set environment variables into config fields, collecting parse errors.
+// Empty values are ignored, so blank entries copied from .env.template keep the file's values.
+type envOverrides struct {
+	errs []error
+}
+
+func (e *envOverrides) fail(key, val string, err error) {
+	e.errs = append(e.errs, fmt.Errorf("invalid %s %q: %v", key, val, err))
+}
+
+func (e *envOverrides) setString(key string, dst *string) {
+	if val, ok := os.LookupEnv(key); ok && val != "" {
+		*dst = val
+	}
+}
+
+// setList sets dst from a comma-separated list; an empty value keeps the file's list
+func (e *envOverrides) setList(key string, dst *[]string) {
+	val, ok := os.LookupEnv(key)
+	if !ok || val == "" {
+		return
+	}
+	var list []string
+	for _, entry := range strings.Split(val, ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			list = append(list, entry)
+		}
+	}
+	*dst = list
+}
+
+// setRoutes replaces dst with a JSON array of routes
+func (e *envOverrides) setRoutes(key string, dst *[]routeJSON) {
+	val, ok := os.LookupEnv(key)
+	if !ok || val == "" {
+		return
+	}
+	var routes []routeJSON
+	if err := json.Unmarshal([]byte(val), &routes); err != nil {
+		e.errs = append(e.errs, fmt.Errorf("invalid %s JSON: %v", key, err))
+		return
+	}
+	*dst = routes
+}
+
+func (e *envOverrides) setUint16(key string, dst *uint16) {
+	if val, ok := os.LookupEnv(key); ok && val != "" {
+		n, err := strconv.ParseUint(val, 10, 16)
+		if err != nil {
+			e.fail(key, val, err)
+			return
+		}
+		*dst = uint16(n)
+	}
+}
+
+func (e *envOverrides) setUint64(key string, dst *uint64) {
+	if val, ok := os.LookupEnv(key); ok && val != "" {
+		n, err := strconv.ParseUint(val, 10, 64)
+		if err != nil {
+			e.fail(key, val, err)
+			return
+		}
+		*dst = n
+	}
+}
+
+func (e *envOverrides) setInt(key string, dst *int) {
+	if val, ok := os.LookupEnv(key); ok && val != "" {
+		n, err := strconv.Atoi(val)
+		if err != nil {
+			e.fail(key, val, err)
+			return
+		}
+		*dst = n
+	}
+}
+
+func (e *envOverrides) setFloat(key string, dst *float64) {
+	if val, ok := os.LookupEnv(key); ok && val != "" {
+		f, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			e.fail(key, val, err)
+			return
+		}
+		*dst = f
+	}
+}
+
+func (e *envOverrides) setDuration(key string, dst *time.Duration) {
+	if val, ok := os.LookupEnv(key); ok && val != "" {
+		d, err := time.ParseDuration(val)
+		if err != nil {
+			e.fail(key, val, err)
+			return
+		}
+		*dst = d
+	}
+}
+
+// Validate checks the configuration without contacting any endpoint, reporting every problem found
+func (c Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(c.SpyRPCHost != "", "spy host is required")
+
+	routes := c.RouteTable()
+	if err := validateRoutes(routes); err != nil {
+		errs = append(errs, fmt.Errorf("routes: %v", err))
+	}
+
+	var hasEVM, hasAztec bool
+	for _, route := range routes {
+		hasEVM = hasEVM || route.Client == RouteClientEVM
+		hasAztec = hasAztec || route.Client == RouteClientAztec
+	}
+
+	if hasEVM {
+		if err := c.Signer.validate(); err != nil {
+			errs = append(errs, fmt.Errorf("evm signer: %v", err))
+		}
+		check(c.EVM.Confirmations > 0, "evm confirmations must be at least 1")
+		check(c.EVM.ReceiptTimeout > 0, "evm receipt timeout must be positive")
+		check(c.EVM.FeeBumpPercent >= evm.MinFeeBumpPercent, "evm fee bump must be at least %d%%", evm.MinFeeBumpPercent)
+		check(c.EVM.GasMultiplier >= 1, "evm gas multiplier must be at least 1")
+		check(c.EVM.GasLimitCeiling == 0 || c.EVM.GasLimitCeiling >= c.EVM.GasLimitFloor,
+			"evm gas limit max %d is below the min %d", c.EVM.GasLimitCeiling, c.EVM.GasLimitFloor)
+	}
+	if hasAztec {
+		check(c.AztecPXEURL != "", "aztec PXE URL is required")
+		check(isAztecAddress(c.AztecWalletAddress), "aztec wallet address %q must be 32 bytes of hex", c.AztecWalletAddress)
+		check(c.VerificationServiceURL != "", "verification service URL is required")
+	}
+
+	switch c.GuardianSetSource {
+	case "contract", "none":
+	case "file":
+		check(c.GuardianSetFile != "", "guardian set file is required when the guardian set source is file")
+	default:
+		check(false, "unknown guardian set source %q", c.GuardianSetSource)
+	}
+	check(c.WormholeCoreContract == "" || common.IsHexAddress(c.WormholeCoreContract),
+		"invalid wormhole core contract %q", c.WormholeCoreContract)
+
+	// A relative default would put delivery state wherever the relayer happens to be started
+	check(c.StorePath != "", "store path is required")
+	check(c.StoreRetention >= 0, "store retention must not be negative")
+	check(c.RetryPolicy.InitialBackoff > 0, "retry initial backoff must be positive")
+	check(c.RetryPolicy.Multiplier >= 1, "retry multiplier must be at least 1")
+	check(c.RetryPolicy.Jitter >= 0 && c.RetryPolicy.Jitter < 1, "retry jitter must be in [0, 1)")
+	check(c.Workers.Count > 0, "worker count must be at least 1")
+	check(c.Workers.QueueSize >= 0, "work queue size must not be negative")
+	check(c.Backfill.MaxGap > 0, "backfill max gap must be at least 1")
+	// The admin API has no TLS, so its bearer token must not cross the network
+	check(c.AdminToken == "" || isLoopbackAddr(c.AdminListenAddr),
+		"admin listen address %q must be a loopback address", c.AdminListenAddr)
+
+	return errors.Join(errs...)
+}
+
+// isLoopbackAddr reports whether the host of a host:port listen address is a loopback address
+func isLoopbackAddr(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// isAztecAddress reports whether s is a 32-byte hex Aztec address
+func isAztecAddress(s string) bool {
+	trimmed := strings.TrimPrefix(s, "0x")
+	if len(trimmed) != 64 {
+		return false
+	}
+	_, err := hex.DecodeString(trimmed)
+	return err == nil
+}
+
+// gweiToWei converts a gwei amount to wei
+func gweiToWei(gwei float64) *big.Int {
+	wei, _ := new(big.Float).Mul(big.NewFloat(gwei), big.NewFloat(1e9)).Int(nil)
+	return wei
+}
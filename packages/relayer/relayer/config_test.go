@@ -0,0 +1,69 @@
+package relayer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestApplyEnvIgnoresEmptyValues(t *testing.T) {
+	fc := defaultFileConfig()
+	fc.Spy.Host = "spy:7073"
+	fc.Store.Path = "/var/lib/relayer.db"
+	t.Setenv("SPY_RPC_HOST", "")
+	t.Setenv("STORE_PATH", "/data/relayer.db")
+
+	if err := fc.applyEnv(); err != nil {
+		t.Fatal(err)
+	}
+	if fc.Spy.Host != "spy:7073" {
+		t.Errorf("empty SPY_RPC_HOST replaced the file's host with %q", fc.Spy.Host)
+	}
+	if fc.Store.Path != "/data/relayer.db" {
+		t.Errorf("store path = %q, want STORE_PATH", fc.Store.Path)
+	}
+}
+
+func TestValidateRequiresStorePath(t *testing.T) {
+	fc := defaultFileConfig()
+	config, err := fc.config()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := config.Validate(); err == nil || !strings.Contains(err.Error(), "store path is required") {
+		t.Errorf("Validate without a store path = %v, want it required", err)
+	}
+}
+
+func TestValidateAdminListenAddr(t *testing.T) {
+	cases := []struct {
+		name    string
+		token   Secret
+		addr    string
+		wantErr bool
+	}{
+		{"admin API disabled", "", ":9091", false},
+		{"IPv4 loopback", "token", "127.0.0.1:9091", false},
+		{"IPv6 loopback", "token", "[::1]:9091", false},
+		{"localhost", "token", "localhost:9091", false},
+		{"every interface", "token", ":9091", true},
+		{"public address", "token", "10.0.0.5:9091", true},
+		{"missing port", "token", "127.0.0.1", true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			fc := defaultFileConfig()
+			config, err := fc.config()
+			if err != nil {
+				t.Fatal(err)
+			}
+			config.AdminToken = tc.token
+			config.AdminListenAddr = tc.addr
+
+			err = config.Validate()
+			gotErr := err != nil && strings.Contains(err.Error(), "admin listen address")
+			if gotErr != tc.wantErr {
+				t.Errorf("Validate = %v, want admin listen address rejected: %v", err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,410 @@
+package relayer
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/wormhole-foundation/wormhole/aztec/relayer/payload"
+	"github.com/wormhole-foundation/wormhole/aztec/relayer/relayertest"
+	vaaLib "github.com/wormhole-foundation/wormhole/sdk/vaa"
+)
+
+// End-to-end tests of Relayer.Start against the in-process fakes in relayertest: a spy,
+// a simulated EVM chain running a Treasury stand-in, a PXE and the verification service.
+
+const (
+	e2eAztecChain   = 56
+	e2eEVMChain     = 10003
+	e2eAztecTarget  = "0x2b13cff4daef709134419f1506ccae28956e02102a5ef5f2d0077e4991a9f493"
+	e2eAztecWallet  = "0x1f3933ca4d66e948ace5f8339e5da687993b76ee57bcf65e82596e0fc10a8859"
+	e2eWaitTimeout  = 30 * time.Second
+	e2eWaitInterval = 50 * time.Millisecond
+)
+
+var (
+	e2eAztecEmitter = vaaLib.Address{31: 0xa1} // Allowlisted on the Aztec -> EVM route
+	e2eEVMEmitter   = vaaLib.Address{31: 0xe1} // Allowlisted on the EVM -> Aztec route
+
+	errPXEDown = errors.New("PXE unavailable")
+)
+
+func TestMain(m *testing.M) {
+	streamRetryDelay = 100 * time.Millisecond
+	backfillRetryDelay = 100 * time.Millisecond
+	retryPollInterval = 50 * time.Millisecond
+	os.Exit(m.Run())
+}
+
+// e2eHarness wires a relayer config to fresh fakes
+type e2eHarness struct {
+	t         *testing.T
+	spy       *relayertest.SpyServer
+	chain     *relayertest.Chain
+	pxe       *relayertest.PXEServer
+	verifier  *relayertest.VerificationServer
+	guardians *relayertest.Guardians
+	config    Config
+	sequence  uint64
+}
+
+func newE2EHarness(t *testing.T) *e2eHarness {
+	key := relayertest.NewKey(t)
+	h := &e2eHarness{
+		t:         t,
+		spy:       relayertest.NewSpyServer(t),
+		chain:     relayertest.NewChain(t, crypto.PubkeyToAddress(key.PublicKey)),
+		pxe:       relayertest.NewPXEServer(t),
+		verifier:  relayertest.NewVerificationServer(t),
+		guardians: relayertest.NewGuardians(t, 0, 3),
+	}
+
+	fc := defaultFileConfig()
+	fc.Spy.Host = h.spy.Addr()
+	fc.Routes = []routeJSON{{
+		Name:           "aztec-to-evm",
+		SourceChain:    e2eAztecChain,
+		Emitters:       []string{e2eAztecEmitter.String()},
+		DestChain:      e2eEVMChain,
+		TargetContract: relayertest.TreasuryAddress.Hex(),
+		Client:         RouteClientEVM,
+		RPCURL:         h.chain.URL(),
+		NetworkID:      relayertest.ChainID,
+	}, {
+		Name:           "evm-to-aztec",
+		SourceChain:    e2eEVMChain,
+		Emitters:       []string{e2eEVMEmitter.String()},
+		DestChain:      e2eAztecChain,
+		TargetContract: e2eAztecTarget,
+		Client:         RouteClientAztec,
+	}}
+	fc.Aztec.PXEURL = h.pxe.URL()
+	fc.Aztec.WalletAddress = e2eAztecWallet
+	fc.Aztec.VerificationServiceURL = h.verifier.URL()
+	fc.Signer.PrivateKey = hex.EncodeToString(crypto.FromECDSA(key))
+	fc.EVM.ReceiptTimeout = e2eWaitTimeout
+	fc.Store.Path = filepath.Join(t.TempDir(), "relayer.db")
+	fc.Retry.InitialBackoff = 100 * time.Millisecond
+	fc.Retry.Jitter = 0
+	fc.Guardians.Source = "file"
+	fc.Guardians.File = h.guardians.WriteSetFile(t)
+	fc.Ops.ListenAddr = ""
+	fc.Health.CheckInterval = 0
+
+	config, err := fc.config()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := config.Validate(); err != nil {
+		t.Fatalf("invalid harness config: %v", err)
+	}
+	h.config = config
+	return h
+}
+
+// e2eRun is a relayer running Start in the background
+type e2eRun struct {
+	t       *testing.T
+	relayer *Relayer
+	cancel  context.CancelFunc
+	done    chan error
+	stopped bool
+}
+
+// start creates a relayer over the harness config and runs it until stop or the end of the test
+func (h *e2eHarness) start() *e2eRun {
+	h.t.Helper()
+
+	relayer, err := New(h.config)
+	if err != nil {
+		h.t.Fatalf("New: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	run := &e2eRun{t: h.t, relayer: relayer, cancel: cancel, done: make(chan error, 1)}
+	go func() {
+		run.done <- relayer.Start(ctx)
+	}()
+	h.t.Cleanup(func() { run.stop() })
+	return run
+}
+
+// stop shuts the relayer down and returns what Start returned
+func (r *e2eRun) stop() error {
+	r.t.Helper()
+
+	if r.stopped {
+		return nil
+	}
+	r.stopped = true
+	r.cancel()
+	defer r.relayer.Close()
+
+	select {
+	case err := <-r.done:
+		return err
+	case <-time.After(e2eWaitTimeout):
+		r.t.Fatal("relayer did not shut down")
+		return nil
+	}
+}
+
+// nextSequence returns a fresh VAA sequence number
+func (h *e2eHarness) nextSequence() uint64 {
+	h.sequence++
+	return h.sequence
+}
+
+// transferVAA returns a guardian-signed MultiSig transfer from the Aztec emitter that pays
+// out on targetChain
+func (h *e2eHarness) transferVAA(targetChain uint16) (*vaaLib.VAA, []byte) {
+	h.t.Helper()
+
+	sequence := h.nextSequence()
+	transfer := &payload.TransferPayload{
+		TxID:        [payload.TxIDLength]byte{0: byte(sequence)},
+		Token:       common.HexToAddress("0x75faf114eafb1BDbe2F0316DF893fd58CE46AA4d"),
+		Recipient:   common.HexToAddress("0x1bF9Dbd1D52fb0AB6a8D1f1B2A7D2b8a3cE8c1F0"),
+		Amount:      big.NewInt(1000),
+		TargetChain: targetChain,
+	}
+	encoded, err := transfer.Encode()
+	if err != nil {
+		h.t.Fatal(err)
+	}
+	v := h.guardians.NewVAA(e2eAztecChain, e2eAztecEmitter, sequence, encoded)
+	return v, h.guardians.Sign(h.t, v)
+}
+
+// aztecVAA returns a guardian-signed VAA from the EVM emitter for the Aztec route
+func (h *e2eHarness) aztecVAA() []byte {
+	h.t.Helper()
+
+	sequence := h.nextSequence()
+	v := h.guardians.NewVAA(e2eEVMChain, e2eEVMEmitter, sequence, make([]byte, payload.Length))
+	return h.guardians.Sign(h.t, v)
+}
+
+// waitFor polls cond until it holds or the wait times out
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(e2eWaitTimeout)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(e2eWaitInterval)
+	}
+}
+
+// waitForStatus waits until the stored record of vaaBytes has status
+func (r *e2eRun) waitForStatus(vaaBytes []byte, status VAAStatus) *VAARecord {
+	r.t.Helper()
+
+	key := computeVAAKey(vaaBytes)
+	var rec *VAARecord
+	waitFor(r.t, "VAA "+key+" to be "+string(status), func() bool {
+		var err error
+		rec, err = r.relayer.store.Get(key)
+		return err == nil && rec.Status == status
+	})
+	return rec
+}
+
+// verifyCalls returns how many verify() transactions the chain has mined
+func (h *e2eHarness) verifyCalls() int {
+	h.t.Helper()
+
+	calls, err := h.chain.VerifyCalls(context.Background())
+	if err != nil {
+		h.t.Fatal(err)
+	}
+	return calls
+}
+
+func TestE2EDeliversOverEachRoute(t *testing.T) {
+	h := newE2EHarness(t)
+	run := h.start()
+
+	transfer, transferBytes := h.transferVAA(e2eEVMChain)
+	aztecBytes := h.aztecVAA()
+	h.spy.Publish(transferBytes)
+	h.spy.Publish(aztecBytes)
+
+	rec := run.waitForStatus(transferBytes, VAAStatusConfirmed)
+	if len(rec.TxHashes) != 1 {
+		t.Errorf("transfer has %d transactions, want 1", len(rec.TxHashes))
+	}
+	processed, err := h.chain.IsMessageProcessed(context.Background(), e2eAztecChain, transfer.EmitterAddress, transfer.Sequence)
+	if err != nil || !processed {
+		t.Errorf("Treasury has not processed the transfer: processed %v, err %v", processed, err)
+	}
+
+	run.waitForStatus(aztecBytes, VAAStatusConfirmed)
+	if verified := h.verifier.Verified(); len(verified) != 1 || string(verified[0]) != string(aztecBytes) {
+		t.Errorf("verification service got %d VAAs, want the Aztec VAA", len(verified))
+	}
+	if sent := h.pxe.Sent(); len(sent) != 0 {
+		t.Errorf("PXE got %d transactions, want none while the verification service is up", len(sent))
+	}
+}
+
+func TestE2EDedupesReplayedVAAs(t *testing.T) {
+	h := newE2EHarness(t)
+	run := h.start()
+
+	_, transferBytes := h.transferVAA(e2eEVMChain)
+	for i := 0; i < 3; i++ {
+		h.spy.Publish(transferBytes)
+	}
+	run.waitForStatus(transferBytes, VAAStatusConfirmed)
+
+	// A replay after delivery is dropped too; the Start loop handles VAAs in order, so once
+	// the next VAA is delivered the replay has been seen
+	h.spy.Publish(transferBytes)
+	_, nextBytes := h.transferVAA(e2eEVMChain)
+	h.spy.Publish(nextBytes)
+	run.waitForStatus(nextBytes, VAAStatusConfirmed)
+
+	if calls := h.verifyCalls(); calls != 2 {
+		t.Errorf("chain mined %d verify() calls, want 2", calls)
+	}
+}
+
+func TestE2EResubscribesAfterSpyDisconnect(t *testing.T) {
+	h := newE2EHarness(t)
+	run := h.start()
+
+	ctx, cancel := context.WithTimeout(context.Background(), e2eWaitTimeout)
+	defer cancel()
+	if err := h.spy.WaitForSubscriptions(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	h.spy.Disconnect()
+	if err := h.spy.WaitForSubscriptions(ctx, 2); err != nil {
+		t.Fatal(err)
+	}
+
+	_, transferBytes := h.transferVAA(e2eEVMChain)
+	h.spy.Publish(transferBytes)
+	run.waitForStatus(transferBytes, VAAStatusConfirmed)
+}
+
+func TestE2ERejectsInvalidVAAs(t *testing.T) {
+	h := newE2EHarness(t)
+	run := h.start()
+
+	// Signed by keys outside the guardian set
+	forged, _ := h.transferVAA(e2eEVMChain)
+	forged.Signatures = nil
+	forgedBytes := relayertest.SignWith(t, forged, relayertest.NewKey(t), relayertest.NewKey(t), relayertest.NewKey(t))
+	h.spy.Publish(forgedBytes)
+
+	// Pays out on a chain no route delivers to
+	_, wrongChainBytes := h.transferVAA(30)
+	h.spy.Publish(wrongChainBytes)
+
+	// Not a MultiSig transfer payload
+	malformed := h.guardians.NewVAA(e2eAztecChain, e2eAztecEmitter, h.nextSequence(), []byte("not a transfer"))
+	malformedBytes := h.guardians.Sign(t, malformed)
+	h.spy.Publish(malformedBytes)
+
+	for _, vaaBytes := range [][]byte{forgedBytes, wrongChainBytes, malformedBytes} {
+		rec := run.waitForStatus(vaaBytes, VAAStatusDeadLettered)
+		if rec.Attempts != 1 {
+			t.Errorf("dead-lettered after %d attempts, want 1", rec.Attempts)
+		}
+	}
+	if calls := h.verifyCalls(); calls != 0 {
+		t.Errorf("chain mined %d verify() calls, want none", calls)
+	}
+}
+
+func TestE2EFallsBackToPXE(t *testing.T) {
+	h := newE2EHarness(t)
+	h.verifier.Fail("prover unavailable")
+	run := h.start()
+
+	aztecBytes := h.aztecVAA()
+	h.spy.Publish(aztecBytes)
+	run.waitForStatus(aztecBytes, VAAStatusConfirmed)
+
+	if sent := h.pxe.Sent(); len(sent) != 1 || string(sent[0]) != string(aztecBytes) {
+		t.Errorf("PXE got %d transactions, want the Aztec VAA", len(sent))
+	}
+}
+
+func TestE2ERetriesFailedDeliveries(t *testing.T) {
+	h := newE2EHarness(t)
+	h.verifier.Fail("prover unavailable")
+	h.pxe.FailSends(errPXEDown)
+	run := h.start()
+
+	aztecBytes := h.aztecVAA()
+	h.spy.Publish(aztecBytes)
+	rec := run.waitForStatus(aztecBytes, VAAStatusFailed)
+	if rec.LastError == "" {
+		t.Error("failed record has no error")
+	}
+
+	h.pxe.FailSends(nil)
+	run.waitForStatus(aztecBytes, VAAStatusConfirmed)
+	if sent := h.pxe.Sent(); len(sent) != 1 {
+		t.Errorf("PXE got %d transactions, want 1", len(sent))
+	}
+}
+
+func TestE2EResumesAfterShutdown(t *testing.T) {
+	h := newE2EHarness(t)
+	h.chain.PauseMining()
+	run := h.start()
+
+	// Shut down while the verify() transaction is still pending
+	_, transferBytes := h.transferVAA(e2eEVMChain)
+	h.spy.Publish(transferBytes)
+	run.waitForStatus(transferBytes, VAAStatusSubmitted)
+	if err := run.stop(); err != nil {
+		t.Fatalf("Start returned %v on shutdown", err)
+	}
+
+	h.chain.ResumeMining()
+	restarted := h.start()
+	restarted.waitForStatus(transferBytes, VAAStatusConfirmed)
+
+	if calls := h.verifyCalls(); calls != 1 {
+		t.Errorf("chain mined %d verify() calls, want 1", calls)
+	}
+}
+
+func TestE2EBackfillsMissedVAAs(t *testing.T) {
+	h := newE2EHarness(t)
+	wormholescan := relayertest.NewWormholescanServer(t)
+	h.config.Backfill.URL = wormholescan.URL()
+	run := h.start()
+
+	_, firstBytes := h.transferVAA(e2eEVMChain)
+	h.spy.Publish(firstBytes)
+	run.waitForStatus(firstBytes, VAAStatusConfirmed)
+
+	// The second VAA never reaches the spy stream; the third reveals the gap
+	_, missedBytes := h.transferVAA(e2eEVMChain)
+	_, thirdBytes := h.transferVAA(e2eEVMChain)
+	wormholescan.Add(t, missedBytes)
+	h.spy.Publish(thirdBytes)
+
+	run.waitForStatus(thirdBytes, VAAStatusConfirmed)
+	run.waitForStatus(missedBytes, VAAStatusConfirmed)
+	if calls := h.verifyCalls(); calls != 3 {
+		t.Errorf("chain mined %d verify() calls, want 3", calls)
+	}
+	if requests := wormholescan.Requests(); requests != 1 {
+		t.Errorf("backfill API got %d requests, want 1", requests)
+	}
+}
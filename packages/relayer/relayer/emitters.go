@@ -0,0 +1,57 @@
+package relayer
+
+import (
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// EmitterAllowlist is a set of emitter addresses, normalized to the 64-character
+// lowercase hex form used by VAAData.EmitterHex
+type EmitterAllowlist map[string]struct{}
+
+// ParseEmitterAllowlist parses a comma-separated list of emitter addresses. Shorter
+// addresses, such as 20-byte EVM contracts, are left-padded to 32 bytes.
+func ParseEmitterAllowlist(list string) (EmitterAllowlist, error) {
+	allowlist := make(EmitterAllowlist)
+	for _, entry := range strings.Split(list, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		emitter, err := normalizeEmitter(entry)
+		if err != nil {
+			return nil, err
+		}
+		allowlist[emitter] = struct{}{}
+	}
+	return allowlist, nil
+}
+
+// Allows reports whether emitterHex is on the allowlist
+func (a EmitterAllowlist) Allows(emitterHex string) bool {
+	_, ok := a[strings.ToLower(emitterHex)]
+	return ok
+}
+
+// List returns the allowlisted emitters in sorted order
+func (a EmitterAllowlist) List() []string {
+	emitters := make([]string, 0, len(a))
+	for emitter := range a {
+		emitters = append(emitters, emitter)
+	}
+	sort.Strings(emitters)
+	return emitters
+}
+
+func normalizeEmitter(address string) (string, error) {
+	trimmed := strings.ToLower(strings.TrimPrefix(strings.TrimPrefix(address, "0x"), "0X"))
+	if len(trimmed) == 0 || len(trimmed) > 64 {
+		return "", fmt.Errorf("invalid emitter address %q: must be 1 to 32 bytes of hex", address)
+	}
+	if _, err := hex.DecodeString(strings.Repeat("0", len(trimmed)%2) + trimmed); err != nil {
+		return "", fmt.Errorf("invalid emitter address %q: %v", address, err)
+	}
+	return strings.Repeat("0", 64-len(trimmed)) + trimmed, nil
+}
@@ -0,0 +1,284 @@
+package relayer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	vaaLib "github.com/wormhole-foundation/wormhole/sdk/vaa"
+	"go.uber.org/zap"
+)
+
+const (
+	// guardianSetRefresh is how long the current guardian set index is cached by EVMGuardianSetProvider
+	guardianSetRefresh = 10 * time.Minute
+	// guardianSetMinRefresh limits how often a VAA from a newer guardian set forces a refresh
+	guardianSetMinRefresh = 30 * time.Second
+)
+
+// ErrUnknownGuardianSet is returned when a provider has no guardian set for an index
+var ErrUnknownGuardianSet = errors.New("unknown guardian set")
+
+// GuardianSet is a set of guardian keys that can sign VAAs
+type GuardianSet struct {
+	Index          uint32           `json:"index"`
+	Keys           []common.Address `json:"keys"`
+	ExpirationTime uint32           `json:"expirationTime"` // Unix time a superseded set stops being valid (0 for the current set)
+}
+
+// GuardianSetProvider supplies the guardian sets VAAs are verified against
+type GuardianSetProvider interface {
+	// CurrentIndex returns the index of the active guardian set
+	CurrentIndex(ctx context.Context) (uint32, error)
+	// GuardianSet returns the guardian set with the given index, or ErrUnknownGuardianSet
+	GuardianSet(ctx context.Context, index uint32) (*GuardianSet, error)
+}
+
+// guardianSetRefresher is implemented by providers that cache the current guardian set index
+type guardianSetRefresher interface {
+	// RefreshCurrentIndex returns the index of the active guardian set, bypassing the cache
+	RefreshCurrentIndex(ctx context.Context) (uint32, error)
+}
+
+// FileGuardianSetProvider serves a single guardian set loaded from a JSON file of the form
+// {"index": 4, "keys": ["0x...", ...]}
+type FileGuardianSetProvider struct {
+	set *GuardianSet
+}
+
+// NewFileGuardianSetProvider loads the guardian set from path
+func NewFileGuardianSetProvider(path string) (*FileGuardianSetProvider, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read guardian set file: %v", err)
+	}
+
+	set := &GuardianSet{}
+	if err := json.Unmarshal(data, set); err != nil {
+		return nil, fmt.Errorf("failed to parse guardian set file: %v", err)
+	}
+	if len(set.Keys) == 0 {
+		return nil, fmt.Errorf("guardian set file %s has no keys", path)
+	}
+
+	return &FileGuardianSetProvider{set: set}, nil
+}
+
+// CurrentIndex returns the index of the guardian set in the file
+func (p *FileGuardianSetProvider) CurrentIndex(ctx context.Context) (uint32, error) {
+	return p.set.Index, nil
+}
+
+// GuardianSet returns the guardian set in the file if its index matches
+func (p *FileGuardianSetProvider) GuardianSet(ctx context.Context, index uint32) (*GuardianSet, error) {
+	if index != p.set.Index {
+		return nil, fmt.Errorf("%w: %d", ErrUnknownGuardianSet, index)
+	}
+	return p.set, nil
+}
+
+// Wormhole core contract ABI for the guardian set getters
+const wormholeCoreABIJSON = `[{
+        "inputs": [],
+        "name": "getCurrentGuardianSetIndex",
+        "outputs": [{"internalType": "uint32", "name": "", "type": "uint32"}],
+        "stateMutability": "view",
+        "type": "function"
+    }, {
+        "inputs": [{"internalType": "uint32", "name": "index", "type": "uint32"}],
+        "name": "getGuardianSet",
+        "outputs": [{
+            "components": [
+                {"internalType": "address[]", "name": "keys", "type": "address[]"},
+                {"internalType": "uint32", "name": "expirationTime", "type": "uint32"}
+            ],
+            "internalType": "struct Structs.GuardianSet",
+            "name": "",
+            "type": "tuple"
+        }],
+        "stateMutability": "view",
+        "type": "function"
+    }, {
+        "inputs": [],
+        "name": "wormhole",
+        "outputs": [{"internalType": "contract IWormhole", "name": "", "type": "address"}],
+        "stateMutability": "view",
+        "type": "function"
+    }]`
+
+// EVMGuardianSetProvider reads guardian sets from a Wormhole core contract on an EVM chain
+type EVMGuardianSetProvider struct {
+	caller   ethereum.ContractCaller
+	abi      abi.ABI
+	treasury common.Address // Used to look up the core contract when none is configured
+
+	mu         sync.Mutex
+	core       common.Address
+	currentIdx uint32
+	currentAt  time.Time
+	sets       map[uint32]*GuardianSet
+	logger     *zap.Logger
+}
+
+// NewEVMGuardianSetProvider creates a provider for the core contract at coreAddress. If
+// coreAddress is empty, the core contract is read from the Treasury's wormhole() getter.
+func NewEVMGuardianSetProvider(caller ethereum.ContractCaller, coreAddress, treasuryAddress string, logger *zap.Logger) (*EVMGuardianSetProvider, error) {
+	parsedABI, err := abi.JSON(strings.NewReader(wormholeCoreABIJSON))
+	if err != nil {
+		return nil, fmt.Errorf("ABI parse error: %v", err)
+	}
+
+	p := &EVMGuardianSetProvider{
+		caller:   caller,
+		abi:      parsedABI,
+		treasury: common.HexToAddress(treasuryAddress),
+		sets:     make(map[uint32]*GuardianSet),
+		logger:   logger.With(zap.String("component", "EVMGuardianSetProvider")),
+	}
+	if coreAddress != "" {
+		if !common.IsHexAddress(coreAddress) {
+			return nil, fmt.Errorf("invalid Wormhole core contract address %q", coreAddress)
+		}
+		p.core = common.HexToAddress(coreAddress)
+	}
+	return p, nil
+}
+
+// CurrentIndex returns the active guardian set index, cached for guardianSetRefresh
+func (p *EVMGuardianSetProvider) CurrentIndex(ctx context.Context) (uint32, error) {
+	return p.currentIndex(ctx, guardianSetRefresh)
+}
+
+// RefreshCurrentIndex reads the active guardian set index from the core contract, unless it
+// was read within guardianSetMinRefresh
+func (p *EVMGuardianSetProvider) RefreshCurrentIndex(ctx context.Context) (uint32, error) {
+	return p.currentIndex(ctx, guardianSetMinRefresh)
+}
+
+// currentIndex returns the active guardian set index, reading it again if it is older than maxAge
+func (p *EVMGuardianSetProvider) currentIndex(ctx context.Context, maxAge time.Duration) (uint32, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.currentAt.IsZero() && time.Since(p.currentAt) < maxAge {
+		return p.currentIdx, nil
+	}
+
+	var index uint32
+	if err := p.call(ctx, &index, "getCurrentGuardianSetIndex"); err != nil {
+		return 0, err
+	}
+	if index != p.currentIdx || p.currentAt.IsZero() {
+		p.logger.Info("Loaded current guardian set index", zap.Uint32("index", index))
+		// The previous set now has an expiration time; fetch it again when needed
+		delete(p.sets, p.currentIdx)
+	}
+	p.currentIdx = index
+	p.currentAt = time.Now()
+	return index, nil
+}
+
+// GuardianSet returns the guardian set with the given index from the core contract
+func (p *EVMGuardianSetProvider) GuardianSet(ctx context.Context, index uint32) (*GuardianSet, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if set, ok := p.sets[index]; ok {
+		return set, nil
+	}
+
+	var out struct {
+		Keys           []common.Address
+		ExpirationTime uint32
+	}
+	if err := p.call(ctx, &out, "getGuardianSet", index); err != nil {
+		return nil, err
+	}
+	if len(out.Keys) == 0 {
+		return nil, fmt.Errorf("%w: %d", ErrUnknownGuardianSet, index)
+	}
+
+	set := &GuardianSet{Index: index, Keys: out.Keys, ExpirationTime: out.ExpirationTime}
+	p.sets[index] = set
+	return set, nil
+}
+
+// call invokes a view on the core contract, resolving its address on first use
+func (p *EVMGuardianSetProvider) call(ctx context.Context, out interface{}, method string, args ...interface{}) error {
+	if p.core == (common.Address{}) {
+		var core common.Address
+		if err := p.callContract(ctx, p.treasury, &core, "wormhole"); err != nil {
+			return fmt.Errorf("failed to read Wormhole core address from Treasury: %v", err)
+		}
+		p.logger.Info("Resolved Wormhole core contract from Treasury", zap.String("core", core.Hex()))
+		p.core = core
+	}
+	return p.callContract(ctx, p.core, out, method, args...)
+}
+
+func (p *EVMGuardianSetProvider) callContract(ctx context.Context, to common.Address, out interface{}, method string, args ...interface{}) error {
+	data, err := p.abi.Pack(method, args...)
+	if err != nil {
+		return fmt.Errorf("ABI pack error: %v", err)
+	}
+
+	result, err := p.caller.CallContract(ctx, ethereum.CallMsg{To: &to, Data: data}, nil)
+	if err != nil {
+		return fmt.Errorf("%s call failed: %v", method, err)
+	}
+
+	values, err := p.abi.Unpack(method, result)
+	if err != nil {
+		return fmt.Errorf("ABI unpack error: %v", err)
+	}
+	if len(values) != 1 {
+		return fmt.Errorf("%s returned %d values", method, len(values))
+	}
+	abi.ConvertType(values[0], out)
+	return nil
+}
+
+// verifyGuardianSignatures checks that a VAA is signed by a quorum of a valid guardian set.
+// Invalid VAAs are reported as permanent errors; provider failures are returned as is. A VAA
+// from a set newer than the cached current one is a transient error, since the guardian set
+// may have been upgraded since the cache was filled.
+func verifyGuardianSignatures(ctx context.Context, provider GuardianSetProvider, v *vaaLib.VAA) error {
+	current, err := provider.CurrentIndex(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get current guardian set: %v", err)
+	}
+	if refresher, ok := provider.(guardianSetRefresher); ok && v.GuardianSetIndex > current {
+		if current, err = refresher.RefreshCurrentIndex(ctx); err != nil {
+			return fmt.Errorf("failed to refresh current guardian set: %v", err)
+		}
+	}
+	if v.GuardianSetIndex > current {
+		return fmt.Errorf("VAA signed by guardian set %d, newer than current set %d", v.GuardianSetIndex, current)
+	}
+
+	set, err := provider.GuardianSet(ctx, v.GuardianSetIndex)
+	if errors.Is(err, ErrUnknownGuardianSet) {
+		return permanent(err)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get guardian set %d: %v", v.GuardianSetIndex, err)
+	}
+
+	// Superseded sets stay valid until their expiration time
+	if v.GuardianSetIndex < current && int64(set.ExpirationTime) < time.Now().Unix() {
+		return permanent(fmt.Errorf("VAA signed by expired guardian set %d", v.GuardianSetIndex))
+	}
+
+	if err := v.Verify(set.Keys); err != nil {
+		return permanent(fmt.Errorf("guardian signature verification failed: %v", err))
+	}
+	return nil
+}
@@ -0,0 +1,248 @@
+package relayer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/wormhole-foundation/wormhole/aztec/relayer/relayertest"
+	vaaLib "github.com/wormhole-foundation/wormhole/sdk/vaa"
+	"go.uber.org/zap"
+)
+
+// staticGuardianSets serves fixed guardian sets
+type staticGuardianSets struct {
+	current uint32
+	sets    map[uint32]*GuardianSet
+}
+
+func (p *staticGuardianSets) CurrentIndex(ctx context.Context) (uint32, error) {
+	return p.current, nil
+}
+
+func (p *staticGuardianSets) GuardianSet(ctx context.Context, index uint32) (*GuardianSet, error) {
+	if set, ok := p.sets[index]; ok {
+		return set, nil
+	}
+	return nil, fmt.Errorf("%w: %d", ErrUnknownGuardianSet, index)
+}
+
+func TestVerifyGuardianSignatures(t *testing.T) {
+	old := relayertest.NewGuardians(t, 1, 4)
+	current := relayertest.NewGuardians(t, 2, 4) // Quorum is 3 of 4
+	next := relayertest.NewGuardians(t, 3, 4)
+	provider := &staticGuardianSets{current: 2, sets: map[uint32]*GuardianSet{
+		1: {Index: 1, Keys: old.Addresses(), ExpirationTime: uint32(time.Now().Add(-time.Hour).Unix())},
+		2: {Index: 2, Keys: current.Addresses()},
+	}}
+	unexpired := &staticGuardianSets{current: 2, sets: map[uint32]*GuardianSet{
+		1: {Index: 1, Keys: old.Addresses(), ExpirationTime: uint32(time.Now().Add(time.Hour).Unix())},
+	}}
+
+	// signed returns a VAA from guardians signed by the guardians at positions
+	signed := func(guardians *relayertest.Guardians, positions ...int) *vaaLib.VAA {
+		v := guardians.NewVAA(e2eAztecChain, e2eAztecEmitter, 1, []byte("payload"))
+		parsed, err := vaaLib.Unmarshal(guardians.SignBy(t, v, positions...))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return parsed
+	}
+	tampered := signed(current, 0, 1, 2)
+	tampered.Payload = []byte("tampered")
+
+	tests := []struct {
+		name      string
+		provider  GuardianSetProvider
+		vaa       *vaaLib.VAA
+		wantErr   bool
+		permanent bool
+	}{
+		{"quorum", provider, signed(current, 0, 1, 3), false, false},
+		{"all guardians", provider, signed(current, 0, 1, 2, 3), false, false},
+		{"below quorum", provider, signed(current, 0, 1), true, true},
+		{"tampered payload", provider, tampered, true, true},
+		{"signed by another set", provider, signed(next, 0, 1, 2, 3), true, false},
+		{"expired set", provider, signed(old, 0, 1, 2, 3), true, true},
+		{"unexpired superseded set", unexpired, signed(old, 0, 1, 2), false, false},
+		{"unknown set", &staticGuardianSets{current: 2}, signed(current, 0, 1, 2), true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := verifyGuardianSignatures(context.Background(), tt.provider, tt.vaa)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("verifyGuardianSignatures = %v, want error %v", err, tt.wantErr)
+			}
+			if err != nil && isPermanent(err) != tt.permanent {
+				t.Errorf("isPermanent(%v) = %v, want %v", err, isPermanent(err), tt.permanent)
+			}
+		})
+	}
+}
+
+func TestFileGuardianSetProvider(t *testing.T) {
+	guardians := relayertest.NewGuardians(t, 4, 3)
+	provider, err := NewFileGuardianSetProvider(guardians.WriteSetFile(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if index, _ := provider.CurrentIndex(context.Background()); index != 4 {
+		t.Errorf("CurrentIndex = %d, want 4", index)
+	}
+	set, err := provider.GuardianSet(context.Background(), 4)
+	if err != nil || len(set.Keys) != 3 || set.Keys[0] != guardians.Addresses()[0] {
+		t.Errorf("GuardianSet(4) = %v, %v, want the file's keys", set, err)
+	}
+	if _, err := provider.GuardianSet(context.Background(), 3); !errors.Is(err, ErrUnknownGuardianSet) {
+		t.Errorf("GuardianSet(3) = %v, want ErrUnknownGuardianSet", err)
+	}
+
+	for name, contents := range map[string]string{
+		"no keys":      `{"index": 4, "keys": []}`,
+		"invalid json": `{"index": 4,`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "guardian-set.json")
+			if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := NewFileGuardianSetProvider(path); err == nil {
+				t.Error("NewFileGuardianSetProvider accepted the file")
+			}
+		})
+	}
+}
+
+// fakeWormholeCore answers the core contract's guardian set getters and the Treasury's wormhole()
+type fakeWormholeCore struct {
+	abi  abi.ABI
+	core common.Address
+
+	mu      sync.Mutex
+	current uint32
+	sets    map[uint32][]common.Address
+	calls   map[string]int
+}
+
+func newFakeWormholeCore(t *testing.T) *fakeWormholeCore {
+	parsed, err := abi.JSON(strings.NewReader(wormholeCoreABIJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &fakeWormholeCore{
+		abi:   parsed,
+		core:  common.HexToAddress("0xc0"),
+		sets:  make(map[uint32][]common.Address),
+		calls: make(map[string]int),
+	}
+}
+
+func (c *fakeWormholeCore) setCurrent(index uint32, keys []common.Address) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.current = index
+	c.sets[index] = keys
+}
+
+func (c *fakeWormholeCore) count(method string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.calls[method]
+}
+
+func (c *fakeWormholeCore) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	method, err := c.abi.MethodById(msg.Data)
+	if err != nil {
+		return nil, err
+	}
+	c.calls[method.Name]++
+	switch method.Name {
+	case "wormhole":
+		return method.Outputs.Pack(c.core)
+	case "getCurrentGuardianSetIndex":
+		return method.Outputs.Pack(c.current)
+	default:
+		if *msg.To != c.core {
+			return nil, fmt.Errorf("%s called on %s, not the core contract", method.Name, msg.To)
+		}
+		args, err := method.Inputs.Unpack(msg.Data[4:])
+		if err != nil {
+			return nil, err
+		}
+		index := args[0].(uint32)
+		var expiration uint32
+		if index < c.current {
+			expiration = uint32(time.Now().Add(time.Hour).Unix())
+		}
+		return method.Outputs.Pack(struct {
+			Keys           []common.Address
+			ExpirationTime uint32
+		}{c.sets[index], expiration})
+	}
+}
+
+func TestEVMGuardianSetProvider(t *testing.T) {
+	ctx := context.Background()
+	core := newFakeWormholeCore(t)
+	first, second := relayertest.NewGuardians(t, 0, 3), relayertest.NewGuardians(t, 1, 3)
+	core.setCurrent(0, first.Addresses())
+
+	// Without a configured core contract, it is read from the Treasury
+	provider, err := NewEVMGuardianSetProvider(core, "", relayertest.TreasuryAddress.Hex(), zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if index, err := provider.CurrentIndex(ctx); err != nil || index != 0 {
+		t.Fatalf("CurrentIndex = %d, %v, want 0", index, err)
+	}
+	set, err := provider.GuardianSet(ctx, 0)
+	if err != nil || set.ExpirationTime != 0 || set.Keys[0] != first.Addresses()[0] {
+		t.Fatalf("GuardianSet(0) = %v, %v, want the first set", set, err)
+	}
+	provider.GuardianSet(ctx, 0)
+	if n := core.count("getGuardianSet"); n != 1 {
+		t.Errorf("getGuardianSet called %d times, want the set cached", n)
+	}
+	if _, err := provider.GuardianSet(ctx, 7); !errors.Is(err, ErrUnknownGuardianSet) {
+		t.Errorf("GuardianSet(7) = %v, want ErrUnknownGuardianSet", err)
+	}
+
+	// A VAA from a set the cached index does not know yet forces a refresh
+	core.setCurrent(1, second.Addresses())
+	v := second.NewVAA(e2eAztecChain, e2eAztecEmitter, 1, nil)
+	parsed, _ := vaaLib.Unmarshal(second.Sign(t, v))
+	if err := verifyGuardianSignatures(ctx, provider, parsed); err == nil || isPermanent(err) {
+		t.Fatalf("VAA from a set newer than the fresh cache: %v, want a transient error", err)
+	}
+	provider.mu.Lock()
+	provider.currentAt = time.Now().Add(-guardianSetMinRefresh)
+	provider.mu.Unlock()
+	if err := verifyGuardianSignatures(ctx, provider, parsed); err != nil {
+		t.Fatalf("VAA from the upgraded set: %v", err)
+	}
+	if index, _ := provider.CurrentIndex(ctx); index != 1 {
+		t.Errorf("CurrentIndex = %d after the refresh, want 1", index)
+	}
+
+	// The superseded set is fetched again to pick up its expiration time
+	if set, err := provider.GuardianSet(ctx, 0); err != nil || set.ExpirationTime == 0 {
+		t.Errorf("GuardianSet(0) = %v, %v after the upgrade, want an expiration time", set, err)
+	}
+	if n := core.count("wormhole"); n != 1 {
+		t.Errorf("wormhole() called %d times, want the core address cached", n)
+	}
+}
@@ -0,0 +1,307 @@
+package relayer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// healthCheckTimeout bounds each dependency probe
+const healthCheckTimeout = 10 * time.Second
+
+// Dependency checks reported by /healthz and /readyz. EVM checks are suffixed with the chain ID.
+const (
+	checkSpy                 = "spy_stream"
+	checkLastVAA             = "last_vaa"
+	checkEVMRPC              = "evm_rpc"
+	checkWalletBalance       = "wallet_balance"
+	checkPXE                 = "aztec_pxe"
+	checkVerificationService = "verification_service"
+	checkStore               = "vaa_store"
+)
+
+// healthProbe checks one dependency, returning a detail message on success
+type healthProbe func(ctx context.Context) (string, error)
+
+// HealthOptions controls the background dependency checks
+type HealthOptions struct {
+	CheckInterval time.Duration // How often dependencies are probed (0 disables the probes)
+	SpyDownGrace  time.Duration // How long the spy stream may be down before liveness fails
+	VAAStaleAfter time.Duration // Readiness fails when no VAA arrived for this long (0 disables)
+	MinBalance    *big.Int      // Readiness fails when the wallet holds less than this, in wei
+}
+
+// checkResult is the outcome of one dependency check
+type checkResult struct {
+	OK        bool      `json:"ok"`
+	Detail    string    `json:"detail,omitempty"`
+	CheckedAt time.Time `json:"checkedAt"`
+}
+
+// healthReport is the JSON body of /healthz and /readyz
+type healthReport struct {
+	Status string                 `json:"status"`
+	Checks map[string]checkResult `json:"checks"`
+}
+
+// healthMonitor tracks the spy stream and periodically probes the relayer's dependencies
+type healthMonitor struct {
+	relayer *Relayer
+	opts    HealthOptions
+	logger  *zap.Logger
+
+	mu           sync.RWMutex
+	spyConnected bool
+	spyDownSince time.Time
+	lastVAAAt    time.Time
+	lastRunAt    time.Time
+	results      map[string]checkResult
+}
+
+func newHealthMonitor(relayer *Relayer, opts HealthOptions, logger *zap.Logger) *healthMonitor {
+	return &healthMonitor{
+		relayer:      relayer,
+		opts:         opts,
+		logger:       logger.With(zap.String("component", "HealthMonitor")),
+		spyDownSince: time.Now(),
+		results:      make(map[string]checkResult),
+	}
+}
+
+// setSpyConnected records whether the spy VAA stream is currently subscribed
+func (h *healthMonitor) setSpyConnected(connected bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.spyConnected && !connected {
+		h.spyDownSince = time.Now()
+	}
+	h.spyConnected = connected
+}
+
+// markVAA records that a VAA arrived from the spy
+func (h *healthMonitor) markVAA() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.lastVAAAt = time.Now()
+}
+
+// run probes dependencies every CheckInterval until ctx is cancelled
+func (h *healthMonitor) run(ctx context.Context, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	if h.opts.CheckInterval <= 0 {
+		h.logger.Info("Background dependency checks disabled")
+		return
+	}
+
+	ticker := time.NewTicker(h.opts.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		h.checkDependencies(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// probes returns the dependency checks for the clients the routes use
+func (h *healthMonitor) probes() map[string]healthProbe {
+	probes := map[string]healthProbe{
+		checkStore: func(ctx context.Context) (string, error) {
+			return "", h.relayer.store.Check()
+		},
+	}
+	for chainID, evmClient := range h.relayer.evmClients {
+		probes[fmt.Sprintf("%s_%d", checkEVMRPC, chainID)] = evmClient.CheckHealth
+		probes[fmt.Sprintf("%s_%d", checkWalletBalance, chainID)] = func(ctx context.Context) (string, error) {
+			return checkBalance(ctx, evmClient, h.opts.MinBalance)
+		}
+	}
+	if h.relayer.aztecClient != nil {
+		probes[checkPXE] = h.relayer.aztecClient.CheckHealth
+		probes[checkVerificationService] = func(ctx context.Context) (string, error) {
+			return "", h.relayer.verificationClient.CheckHealth(ctx)
+		}
+	}
+	return probes
+}
+
+// checkDependencies probes every dependency concurrently and stores the results
+func (h *healthMonitor) checkDependencies(ctx context.Context) {
+	probes := h.probes()
+
+	results := make(map[string]checkResult, len(probes))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, probe := range probes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			probeCtx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+			defer cancel()
+
+			detail, err := probe(probeCtx)
+			result := checkResult{OK: err == nil, Detail: detail, CheckedAt: time.Now()}
+			if err != nil {
+				result.Detail = err.Error()
+			}
+
+			mu.Lock()
+			results[name] = result
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	if ctx.Err() != nil {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for name, result := range results {
+		if prev, ok := h.results[name]; ok && prev.OK != result.OK {
+			if result.OK {
+				h.logger.Info("Dependency recovered", zap.String("check", name))
+			} else {
+				h.logger.Warn("Dependency unhealthy", zap.String("check", name), zap.String("detail", result.Detail))
+			}
+		}
+		h.results[name] = result
+	}
+	h.lastRunAt = time.Now()
+}
+
+// checkBalance reports whether the client's wallet holds at least min wei
+func checkBalance(ctx context.Context, client EVMSubmitter, min *big.Int) (string, error) {
+	balance, err := client.Balance(ctx)
+	if err != nil {
+		return "", err
+	}
+	if min != nil && balance.Cmp(min) < 0 {
+		return "", fmt.Errorf("balance %s wei is below the %s wei threshold", balance, min)
+	}
+	return fmt.Sprintf("balance %s wei", balance), nil
+}
+
+// liveness reports whether the relayer is working at all: the checker is running and the
+// spy stream has not been down for longer than SpyDownGrace
+func (h *healthMonitor) liveness() healthReport {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	now := time.Now()
+	checks := map[string]checkResult{
+		checkSpy: h.spyCheck(now, h.opts.SpyDownGrace),
+	}
+
+	if h.opts.CheckInterval > 0 && !h.lastRunAt.IsZero() && now.Sub(h.lastRunAt) > 3*h.opts.CheckInterval {
+		checks["health_checker"] = checkResult{
+			Detail:    fmt.Sprintf("last run %s ago", now.Sub(h.lastRunAt).Round(time.Second)),
+			CheckedAt: now,
+		}
+	}
+
+	return newHealthReport(checks)
+}
+
+// readiness reports whether the relayer can deliver VAAs right now. Aztec delivery needs
+// either the verification service or the PXE, since one falls back to the other.
+func (h *healthMonitor) readiness() healthReport {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	now := time.Now()
+	checks := map[string]checkResult{
+		checkSpy:     h.spyCheck(now, 0),
+		checkLastVAA: h.lastVAACheck(now),
+	}
+	for name, result := range h.results {
+		checks[name] = result
+	}
+	if h.opts.CheckInterval > 0 {
+		for name := range h.probes() {
+			if _, ok := checks[name]; !ok {
+				checks[name] = checkResult{Detail: "not checked yet", CheckedAt: now}
+			}
+		}
+	}
+
+	report := healthReport{Status: "ok", Checks: checks}
+	for name, result := range checks {
+		if !result.OK && name != checkPXE && name != checkVerificationService {
+			report.Status = "fail"
+		}
+	}
+	if pxe, ok := checks[checkPXE]; ok && !pxe.OK && !checks[checkVerificationService].OK {
+		report.Status = "fail"
+	}
+	return report
+}
+
+// spyCheck reports the stream state, failing once it has been down for longer than grace
+func (h *healthMonitor) spyCheck(now time.Time, grace time.Duration) checkResult {
+	if h.spyConnected {
+		return checkResult{OK: true, Detail: "subscribed", CheckedAt: now}
+	}
+	down := now.Sub(h.spyDownSince)
+	return checkResult{
+		OK:        down < grace,
+		Detail:    fmt.Sprintf("disconnected for %s", down.Round(time.Second)),
+		CheckedAt: now,
+	}
+}
+
+// lastVAACheck reports the time since the last VAA, failing only if VAAStaleAfter is set
+func (h *healthMonitor) lastVAACheck(now time.Time) checkResult {
+	if h.lastVAAAt.IsZero() {
+		return checkResult{OK: h.opts.VAAStaleAfter <= 0, Detail: "no VAA received yet", CheckedAt: now}
+	}
+	since := now.Sub(h.lastVAAAt)
+	return checkResult{
+		OK:        h.opts.VAAStaleAfter <= 0 || since < h.opts.VAAStaleAfter,
+		Detail:    fmt.Sprintf("last VAA %s ago", since.Round(time.Second)),
+		CheckedAt: now,
+	}
+}
+
+func newHealthReport(checks map[string]checkResult) healthReport {
+	report := healthReport{Status: "ok", Checks: checks}
+	for _, result := range checks {
+		if !result.OK {
+			report.Status = "fail"
+		}
+	}
+	return report
+}
+
+// handleHealthz serves the liveness report
+func (h *healthMonitor) handleHealthz(w http.ResponseWriter, req *http.Request) {
+	writeHealthReport(w, h.liveness())
+}
+
+// handleReadyz serves the readiness report
+func (h *healthMonitor) handleReadyz(w http.ResponseWriter, req *http.Request) {
+	writeHealthReport(w, h.readiness())
+}
+
+func writeHealthReport(w http.ResponseWriter, report healthReport) {
+	w.Header().Set("Content-Type", "application/json")
+	if report.Status != "ok" {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(report)
+}
@@ -0,0 +1,69 @@
+package relayer
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestReadyzFollowsSpyStreamAndStore(t *testing.T) {
+	store, err := NewBoltVAAStore(filepath.Join(t.TempDir(), "relayer.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	h := newHealthMonitor(&Relayer{store: store}, HealthOptions{CheckInterval: time.Minute}, zap.NewNop())
+
+	// readyz returns the status code and the failing checks
+	readyz := func() (int, []string) {
+		w := httptest.NewRecorder()
+		h.handleReadyz(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+		var report healthReport
+		if err := json.NewDecoder(w.Body).Decode(&report); err != nil {
+			t.Fatal(err)
+		}
+		var failing []string
+		for name, result := range report.Checks {
+			if !result.OK {
+				failing = append(failing, name)
+			}
+		}
+		sort.Strings(failing)
+		return w.Code, failing
+	}
+
+	steps := []struct {
+		name        string
+		change      func()
+		wantCode    int
+		wantFailing []string
+	}{
+		{"spy not subscribed yet", func() {}, http.StatusServiceUnavailable, []string{checkSpy, checkStore}},
+		{"store not checked yet", func() { h.setSpyConnected(true) }, http.StatusServiceUnavailable, []string{checkStore}},
+		{"ready", func() { h.checkDependencies(context.Background()) }, http.StatusOK, nil},
+		{"spy stream lost", func() { h.setSpyConnected(false) }, http.StatusServiceUnavailable, []string{checkSpy}},
+		{"spy stream back", func() { h.setSpyConnected(true) }, http.StatusOK, nil},
+		{"store closed", func() {
+			store.Close()
+			h.checkDependencies(context.Background())
+		}, http.StatusServiceUnavailable, []string{checkStore}},
+	}
+	for _, step := range steps {
+		step.change()
+		code, failing := readyz()
+		if code != step.wantCode {
+			t.Errorf("%s: /readyz returned %d, want %d", step.name, code, step.wantCode)
+		}
+		if !reflect.DeepEqual(failing, step.wantFailing) {
+			t.Errorf("%s: failing checks %v, want %v", step.name, failing, step.wantFailing)
+		}
+	}
+}
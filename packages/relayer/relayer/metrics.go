@@ -0,0 +1,113 @@
+package relayer
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	vaasReceivedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "relayer",
+		Name:      "vaas_received_total",
+		Help:      "VAAs received from the spy stream, by route",
+	}, []string{"route"})
+
+	vaasDedupedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "relayer",
+		Name:      "vaas_deduped_total",
+		Help:      "VAAs skipped as duplicates of recent or in-flight VAAs, by route",
+	}, []string{"route"})
+
+	vaasFilteredTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "relayer",
+		Name:      "vaas_filtered_total",
+		Help:      "VAAs dropped before delivery, by route and reason",
+	}, []string{"route", "reason"})
+
+	vaasDeliveredTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "relayer",
+		Name:      "vaas_delivered_total",
+		Help:      "VAAs delivered to the destination chain, by route",
+	}, []string{"route"})
+
+	vaasFailedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "relayer",
+		Name:      "vaas_failed_total",
+		Help:      "Failed delivery attempts, by route",
+	}, []string{"route"})
+
+	vaasInFlight = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "relayer",
+		Name:      "vaas_in_flight",
+		Help:      "VAAs currently being processed",
+	})
+
+	workQueueDepth = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "relayer",
+		Name:      "work_queue_depth",
+		Help:      "VAAs waiting in the work queue for a delivery worker",
+	})
+
+	workQueueWait = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: "relayer",
+		Name:      "work_queue_wait_seconds",
+		Help:      "Time VAAs spent in the work queue before a worker picked them up",
+		Buckets:   prometheus.ExponentialBuckets(0.001, 4, 10), // 1ms to ~4min
+	})
+
+	workQueueBlockedSeconds = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "relayer",
+		Name:      "work_queue_blocked_seconds_total",
+		Help:      "Time spent waiting to queue VAAs because the work queue was full",
+	})
+
+	vaasMissedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "relayer",
+		Name:      "vaas_missed_total",
+		Help:      "Sequences found missing from the spy stream, by route",
+	}, []string{"route"})
+
+	vaasBackfilledTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "relayer",
+		Name:      "vaas_backfilled_total",
+		Help:      "Missing VAAs fetched from the backfill API, by route and result",
+	}, []string{"route", "result"})
+
+	deliveryLatency = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "relayer",
+		Name:      "delivery_latency_seconds",
+		Help:      "Time from the VAA timestamp to confirmed delivery, by route",
+		Buckets:   prometheus.ExponentialBuckets(5, 2, 12), // 5s to ~3h
+	}, []string{"route"})
+
+	spyReconnectsTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "relayer",
+		Name:      "spy_reconnects_total",
+		Help:      "Resubscriptions to the spy VAA stream",
+	})
+
+	aztecSubmissionsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "relayer",
+		Name:      "aztec_submissions_total",
+		Help:      "Aztec verify submissions, by path (verification_service or pxe_fallback) and result",
+	}, []string{"path", "result"})
+)
+
+// observeDelivery records a confirmed delivery and its latency from the VAA timestamp
+func observeDelivery(routeName string, vaaTimestamp time.Time) {
+	vaasDeliveredTotal.WithLabelValues(routeName).Inc()
+	if !vaaTimestamp.IsZero() {
+		deliveryLatency.WithLabelValues(routeName).Observe(time.Since(vaaTimestamp).Seconds())
+	}
+}
+
+// observeAztecSubmission counts a submission attempt on one of the Aztec paths
+func observeAztecSubmission(path string, err error) {
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+	aztecSubmissionsTotal.WithLabelValues(path, result).Inc()
+}
@@ -0,0 +1,81 @@
+package relayer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.uber.org/zap"
+)
+
+// opsShutdownTimeout bounds how long in-flight scrapes may delay shutdown
+const opsShutdownTimeout = 5 * time.Second
+
+// startOpsServer serves the operational HTTP endpoints, and the admin API on its own listener,
+// until ctx is cancelled
+func (r *Relayer) startOpsServer(ctx context.Context, wg *sync.WaitGroup) error {
+	var opsListener, adminListener net.Listener
+	var err error
+	if r.config.OpsListenAddr != "" {
+		if opsListener, err = net.Listen("tcp", r.config.OpsListenAddr); err != nil {
+			return fmt.Errorf("failed to listen on %s: %v", r.config.OpsListenAddr, err)
+		}
+	} else {
+		r.logger.Info("Ops HTTP server disabled")
+	}
+	if r.config.AdminToken != "" {
+		if adminListener, err = net.Listen("tcp", r.config.AdminListenAddr); err != nil {
+			if opsListener != nil {
+				opsListener.Close()
+			}
+			return fmt.Errorf("failed to listen on %s: %v", r.config.AdminListenAddr, err)
+		}
+	} else {
+		r.logger.Info("Admin API disabled, set ADMIN_TOKEN to enable it")
+	}
+
+	if opsListener != nil {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", promhttp.Handler())
+		mux.HandleFunc("/healthz", r.health.handleHealthz)
+		mux.HandleFunc("/readyz", r.health.handleReadyz)
+		r.serveHTTP(ctx, wg, "ops", opsListener, mux)
+	}
+	if adminListener != nil {
+		mux := http.NewServeMux()
+		r.registerAdminRoutes(mux)
+		r.serveHTTP(ctx, wg, "admin", adminListener, mux)
+	}
+	return nil
+}
+
+// serveHTTP serves handler on listener until ctx is cancelled
+func (r *Relayer) serveHTTP(ctx context.Context, wg *sync.WaitGroup, name string, listener net.Listener, handler http.Handler) {
+	server := &http.Server{
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			r.logger.Error("HTTP server failed", zap.String("server", name), zap.Error(err))
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		<-ctx.Done()
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), opsShutdownTimeout)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	r.logger.Info("Serving HTTP endpoints", zap.String("server", name), zap.String("addr", listener.Addr().String()))
+}
@@ -0,0 +1,180 @@
+package relayer
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"go.uber.org/zap"
+)
+
+// errSequenceHeld is returned by the processor for VAAs of an ordered route that must wait for
+// an earlier sequence from the same emitter
+var errSequenceHeld = errors.New("waiting for an earlier sequence")
+
+// emitterID identifies an emitter across chains
+func emitterID(chainID uint16, emitterHex string) string {
+	return fmt.Sprintf("%d/%s", chainID, emitterHex)
+}
+
+// awaitTurn holds a VAA of an ordered route until every earlier sequence of its emitter has
+// been delivered, dead-lettered or skipped. Sequences at or below the next one pass.
+func (r *Relayer) awaitTurn(route Route, vaaData *VAAData) error {
+	next, err := r.nextSequence(route, vaaData.ChainID, vaaData.EmitterHex)
+	if err != nil {
+		return err
+	}
+	if vaaData.Sequence <= next {
+		return nil
+	}
+	r.logger.Info("Holding VAA until earlier sequences are delivered",
+		zap.String("route", route.Name),
+		zap.String("vaaHash", vaaData.Key),
+		zap.Uint64("sequence", vaaData.Sequence),
+		zap.Uint64("waitingFor", next))
+	return errSequenceHeld
+}
+
+// nextSequence returns the next sequence to deliver from an emitter. The first time an emitter
+// is seen it follows the highest finished sequence in the store or, without one, starts at the
+// route's configured start sequence. It never starts from whichever VAA happens to arrive first.
+func (r *Relayer) nextSequence(route Route, chainID uint16, emitterHex string) (uint64, error) {
+	r.sequencesMu.Lock()
+	defer r.sequencesMu.Unlock()
+
+	id := emitterID(chainID, emitterHex)
+	if next, ok := r.nextSequences[id]; ok {
+		return next, nil
+	}
+
+	records, err := r.store.ListEmitter(chainID, emitterHex, 0, math.MaxUint64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load sequences of emitter %s: %v", id, err)
+	}
+	next, finished := route.StartSequences[emitterHex], false
+	for _, rec := range records {
+		if rec.SignatureRejected {
+			continue // Possibly forged; the genuine VAA with its sequence may still come
+		}
+		if rec.Status != VAAStatusConfirmed && rec.Status != VAAStatusDeadLettered {
+			continue
+		}
+		if !finished || rec.Sequence >= next {
+			next, finished = rec.Sequence+1, true
+		}
+	}
+	r.nextSequences[id] = next
+	return next, nil
+}
+
+// advanceSequence moves an ordered emitter past sequence once it is finished and dispatches the
+// VAAs held for the sequence after it
+func (r *Relayer) advanceSequence(chainID uint16, emitterHex string, sequence uint64) {
+	id := emitterID(chainID, emitterHex)
+
+	r.sequencesMu.Lock()
+	next, ok := r.nextSequences[id]
+	if !ok || next != sequence {
+		r.sequencesMu.Unlock()
+		return
+	}
+	r.nextSequences[id] = sequence + 1
+	r.sequencesMu.Unlock()
+
+	r.logger.Debug("Ordered emitter advanced",
+		zap.String("emitter", id),
+		zap.Uint64("nextSequence", sequence+1))
+	held, err := r.store.ListEmitter(chainID, emitterHex, sequence+1, sequence+1)
+	if err != nil {
+		r.logger.Error("Failed to list held VAAs", zap.String("emitter", id), zap.Error(err))
+		return
+	}
+	r.releaseHeld(held)
+}
+
+// recheckHeld dispatches a VAA that was just held again if its emitter advanced in the meantime,
+// since advanceSequence skips VAAs that are still in flight
+func (r *Relayer) recheckHeld(key string) {
+	rec, err := r.store.Get(key)
+	if err != nil {
+		return
+	}
+	r.sequencesMu.Lock()
+	next, ok := r.nextSequences[emitterID(rec.ChainID, rec.EmitterHex)]
+	r.sequencesMu.Unlock()
+	if !ok || rec.Sequence > next {
+		return
+	}
+	r.releaseHeld([]*VAARecord{rec})
+}
+
+// releaseHeld dispatches the records that are still held. Dispatching can wait for room in the
+// work queue, so it runs in the background rather than in the worker that finished a VAA.
+func (r *Relayer) releaseHeld(records []*VAARecord) {
+	for _, rec := range records {
+		if rec.Status != VAAStatusReceived || !r.beginProcessingVAA(rec.Key) {
+			continue
+		}
+		r.dispatchInBackground(rec.RawBytes, rec.Key)
+	}
+}
+
+// SkipSequence lets an ordered emitter move past a sequence that will not be delivered, such as
+// one that never arrived or keeps failing. A stored VAA with that sequence is dead-lettered.
+func (r *Relayer) SkipSequence(chainID uint16, emitterHex string, sequence uint64) error {
+	emitter, err := normalizeEmitter(emitterHex)
+	if err != nil {
+		return err
+	}
+	id := emitterID(chainID, emitter)
+
+	r.sequencesMu.Lock()
+	next, ok := r.nextSequences[id]
+	r.sequencesMu.Unlock()
+	if !ok {
+		return fmt.Errorf("emitter %s has no ordered deliveries", id)
+	}
+	if sequence != next {
+		return fmt.Errorf("emitter %s is waiting for sequence %d, not %d", id, next, sequence)
+	}
+
+	records, err := r.store.ListEmitter(chainID, emitter, sequence, sequence)
+	if err != nil {
+		return fmt.Errorf("failed to list VAAs: %v", err)
+	}
+	var skipped []string
+	for _, rec := range records {
+		if rec.Status == VAAStatusConfirmed || rec.Status == VAAStatusDeadLettered {
+			continue
+		}
+		if r.isInFlight(rec.Key) {
+			return fmt.Errorf("vaa %s with sequence %d is in flight", rec.Key, sequence)
+		}
+		skipped = append(skipped, rec.Key)
+	}
+	for _, key := range skipped {
+		r.updateRecord(key, func(rec *VAARecord) {
+			rec.Status = VAAStatusDeadLettered
+			rec.LastError = "sequence skipped by operator"
+		})
+	}
+
+	r.logger.Warn("Sequence skipped",
+		zap.String("emitter", id),
+		zap.Uint64("sequence", sequence),
+		zap.Strings("deadLettered", skipped))
+	r.advanceSequence(chainID, emitter, sequence)
+	return nil
+}
+
+// NextSequences returns the next sequence each ordered emitter is waiting for, by "chain/emitter"
+func (r *Relayer) NextSequences() map[string]uint64 {
+	r.sequencesMu.Lock()
+	defer r.sequencesMu.Unlock()
+
+	out := make(map[string]uint64, len(r.nextSequences))
+	for id, next := range r.nextSequences {
+		out[id] = next
+	}
+	return out
+}
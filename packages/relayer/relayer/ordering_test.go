@@ -0,0 +1,175 @@
+package relayer
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/wormhole-foundation/wormhole/aztec/relayer/payload"
+	"github.com/wormhole-foundation/wormhole/aztec/relayer/relayertest"
+)
+
+// orderEVMToAztec makes the EVM -> Aztec route deliver in sequence order, starting at the
+// harness's first sequence
+func orderEVMToAztec(fc *fileConfig) {
+	fc.Routes[1].Ordered = true
+	fc.Routes[1].StartSequences = map[string]uint64{e2eEVMEmitter.String(): 1}
+}
+
+// startOrderedRun starts a fake run whose EVM -> Aztec route delivers in sequence order and
+// does not retry failed deliveries within the test
+func startOrderedRun(t *testing.T) *fakeRun {
+	return startFakeRun(t, func(fc *fileConfig) {
+		orderEVMToAztec(fc)
+		fc.Retry.InitialBackoff = time.Hour
+	})
+}
+
+// waitForHeld waits until vaaBytes is recorded, not in flight and not yet delivered
+func (f *fakeRun) waitForHeld(vaaBytes []byte) {
+	f.e2eRun.t.Helper()
+
+	key := computeVAAKey(vaaBytes)
+	waitFor(f.e2eRun.t, "VAA "+key+" to be held", func() bool {
+		rec, err := f.relayer.store.Get(key)
+		return err == nil && rec.Status == VAAStatusReceived && !f.relayer.isInFlight(key)
+	})
+}
+
+func TestOrderedRouteHoldsLaterSequences(t *testing.T) {
+	f := startOrderedRun(t)
+
+	first, second, third := f.aztecVAA(), f.aztecVAA(), f.aztecVAA()
+	f.source.Publish(first)
+	f.waitForStatus(first, VAAStatusConfirmed)
+
+	f.source.Publish(third)
+	f.waitForHeld(third)
+	if n := len(f.verifier.Verified()); n != 1 {
+		t.Fatalf("verification service got %d VAAs before the second sequence, want 1", n)
+	}
+
+	f.source.Publish(second)
+	f.waitForStatus(third, VAAStatusConfirmed)
+	verified := f.verifier.Verified()
+	if len(verified) != 3 || string(verified[1]) != string(second) || string(verified[2]) != string(third) {
+		t.Errorf("verification service got %d VAAs, want the three sequences in order", len(verified))
+	}
+}
+
+func TestOrderedRouteSkipsBlockedSequence(t *testing.T) {
+	f := startOrderedRun(t)
+
+	first := f.aztecVAA()
+	f.source.Publish(first)
+	f.waitForStatus(first, VAAStatusConfirmed)
+
+	f.verifier.Fail(errors.New("prover down"))
+	f.aztec.FailSends(errors.New("PXE down"))
+	blocked, next := f.aztecVAA(), f.aztecVAA()
+	f.source.Publish(blocked)
+	blockedRec := f.waitForStatus(blocked, VAAStatusFailed)
+	f.source.Publish(next)
+	f.waitForHeld(next)
+
+	f.verifier.Fail(nil)
+	f.aztec.FailSends(nil)
+	if err := f.relayer.SkipSequence(blockedRec.ChainID, blockedRec.EmitterHex, blockedRec.Sequence+1); err == nil {
+		t.Error("skipping a sequence the emitter is not waiting for succeeded")
+	}
+	if err := f.relayer.SkipSequence(blockedRec.ChainID, blockedRec.EmitterHex, blockedRec.Sequence); err != nil {
+		t.Fatalf("SkipSequence: %v", err)
+	}
+
+	f.waitForStatus(blocked, VAAStatusDeadLettered)
+	f.waitForStatus(next, VAAStatusConfirmed)
+}
+
+func TestOrderedRouteIgnoresForgedSequence(t *testing.T) {
+	f := startOrderedRun(t)
+
+	first := f.aztecVAA()
+	f.source.Publish(first)
+	f.waitForStatus(first, VAAStatusConfirmed)
+
+	// A VAA claiming the next sequence, signed by keys outside the guardian set
+	sequence := f.nextSequence()
+	forgedVAA := f.guardians.NewVAA(e2eEVMChain, e2eEVMEmitter, sequence, make([]byte, payload.Length))
+	forgedVAA.Payload[0] = 0xff
+	forged := relayertest.SignWith(t, forgedVAA, relayertest.NewKey(t), relayertest.NewKey(t))
+	f.source.Publish(forged)
+	if rec := f.waitForStatus(forged, VAAStatusDeadLettered); !rec.SignatureRejected {
+		t.Errorf("forged VAA dead-lettered with %q, want it marked as rejected for its signatures", rec.LastError)
+	}
+	id := emitterID(e2eEVMChain, e2eEVMEmitter.String())
+	if next := f.relayer.NextSequences()[id]; next != sequence {
+		t.Fatalf("emitter moved on to sequence %d after a forged VAA, want it still waiting for %d", next, sequence)
+	}
+
+	third := f.aztecVAA()
+	f.source.Publish(third)
+	f.waitForHeld(third)
+
+	genuine := f.guardians.Sign(t, f.guardians.NewVAA(e2eEVMChain, e2eEVMEmitter, sequence, make([]byte, payload.Length)))
+	f.source.Publish(genuine)
+	f.waitForStatus(third, VAAStatusConfirmed)
+	verified := f.verifier.Verified()
+	if len(verified) != 3 || string(verified[1]) != string(genuine) || string(verified[2]) != string(third) {
+		t.Errorf("verification service got %d VAAs, want the genuine sequences in order", len(verified))
+	}
+}
+
+func TestNextSequenceStartsFromStoreOrConfig(t *testing.T) {
+	emitter := e2eEVMEmitter.String()
+	record := func(sequence uint64, status VAAStatus, signatureRejected bool) *VAARecord {
+		return &VAARecord{ChainID: e2eEVMChain, EmitterHex: emitter, Sequence: sequence, Status: status, SignatureRejected: signatureRejected}
+	}
+
+	tests := []struct {
+		name    string
+		start   map[string]uint64
+		records []*VAARecord
+		want    uint64
+	}{
+		{"new emitter", nil, nil, 0},
+		{"configured start", map[string]uint64{emitter: 40}, nil, 40},
+		{"unfinished VAAs do not set the start", map[string]uint64{emitter: 40}, []*VAARecord{
+			record(45, VAAStatusReceived, false),
+			record(47, VAAStatusFailed, false),
+		}, 40},
+		{"highest finished sequence", map[string]uint64{emitter: 40}, []*VAARecord{
+			record(41, VAAStatusConfirmed, false),
+			record(43, VAAStatusDeadLettered, false),
+			record(44, VAAStatusFailed, false),
+		}, 44},
+		{"forged VAA does not count as finished", nil, []*VAARecord{
+			record(3, VAAStatusConfirmed, false),
+			record(4, VAAStatusDeadLettered, true),
+		}, 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := NewMemoryVAAStore()
+			for i, rec := range tt.records {
+				err := store.Update(fmt.Sprint(i), func(stored *VAARecord) error {
+					*stored = *rec
+					return nil
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+			r := &Relayer{store: store, nextSequences: make(map[string]uint64)}
+			route := Route{Name: "ordered", Ordered: true, StartSequences: tt.start}
+
+			next, err := r.nextSequence(route, e2eEVMChain, emitter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if next != tt.want {
+				t.Errorf("nextSequence = %d, want %d", next, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,1113 @@
+// Package relayer is the relayer core: it subscribes to signed VAAs, matches them against
+// the route table, verifies guardian signatures and delivers them to EVM Treasuries or Aztec,
+// keeping delivery state in a VAAStore so it survives restarts.
+package relayer
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/wormhole-foundation/wormhole/aztec/relayer/aztec"
+	"github.com/wormhole-foundation/wormhole/aztec/relayer/evm"
+	"github.com/wormhole-foundation/wormhole/aztec/relayer/payload"
+	"github.com/wormhole-foundation/wormhole/aztec/relayer/spy"
+	"github.com/wormhole-foundation/wormhole/aztec/relayer/verification"
+	"github.com/wormhole-foundation/wormhole/aztec/relayer/wormholescan"
+	vaaLib "github.com/wormhole-foundation/wormhole/sdk/vaa"
+	"go.uber.org/zap"
+)
+
+// Config holds all configuration parameters for the relayer
+type Config struct {
+	SpyRPCHost             string           // Wormhole spy service endpoint
+	SourceChainID          uint16           // Aztec chain ID
+	DestChainID            uint16           // Arbitrum chain ID
+	AztecPXEURL            string           // PXE URL for Aztec
+	AztecWalletAddress     string           // Aztec wallet address to use
+	ArbitrumRPCURL         string           // RPC URL for Arbitrum
+	Signer                 SignerConfig     // Signer for EVM transactions
+	AztecTargetContract    string           // Target contract on Aztec
+	ArbitrumTargetContract string           // Target contract on Arbitrum
+	ArbitrumNetworkID      uint64           // EVM network ID of ArbitrumRPCURL (0 uses the known ID of DestChainID)
+	AztecEmitters          EmitterAllowlist // Emitters allowed to send Aztec->Arbitrum
+	ArbitrumEmitters       EmitterAllowlist // Emitters allowed to send Arbitrum->Aztec
+	Routes                 []Route          // Route table; empty uses the Aztec/Arbitrum pair above
+	VerificationServiceURL string           // ADD: Verification service URL
+	StorePath              string           // Path to the VAA store database
+	StoreRetention         time.Duration    // How long confirmed VAAs are kept in the store (0 keeps them forever)
+	RetryPolicy            RetryPolicy      // Backoff and dead-letter policy for failed deliveries
+	Workers                WorkerOptions    // Delivery worker pool and its bounded queue
+	Backfill               BackfillOptions  // Where VAAs missing from the spy stream are fetched from
+	EVM                    evm.Options      // Transaction handling for the EVM client
+	GuardianSetSource      string           // Where guardian sets come from: "contract", "file" or "none"
+	GuardianSetFile        string           // Guardian set JSON file when GuardianSetSource is "file"
+	WormholeCoreContract   string           // Wormhole core contract on Arbitrum ("" reads it from the Treasury)
+	OpsListenAddr          string           // Listen address for the /metrics, /healthz and /readyz endpoints ("" disables them)
+	Health                 HealthOptions    // Background dependency checks behind /healthz and /readyz
+	AdminToken             Secret           // Bearer token for the /admin API ("" disables it)
+	AdminListenAddr        string           // Listen address for the /admin API, which is served without TLS so must be loopback
+}
+
+// VAAData encapsulates a VAA and its metadata
+type VAAData struct {
+	VAA        *vaaLib.VAA // The parsed VAA
+	RawBytes   []byte      // Raw VAA bytes
+	ChainID    uint16      // Source chain ID
+	EmitterHex string      // Hex-encoded emitter address
+	Sequence   uint64      // VAA sequence number
+	TxID       string      // Source transaction ID
+	Key        string      // Dedupe and store key (see computeVAAKey)
+}
+
+// Relayer coordinates processing VAAs from the spy service
+type Relayer struct {
+	spyClient          VAASource
+	aztecClient        AztecSubmitter
+	evmClients         map[uint16]EVMSubmitter // By destination chain
+	signer             evm.Signer              // Signs for the EVM clients; nil when every submitter is injected
+	verificationClient VerificationService     // ADD: HTTP verification client
+	config             Config
+	vaaProcessor       func(*Relayer, *VAAData) error
+	logger             *zap.Logger
+	// Route table; emitter allowlists can be replaced at runtime.
+	routesMu sync.RWMutex
+	routes   []Route
+	// Protect against duplicate deliveries from the spy service (at-least-once semantics).
+	dedupeMu      sync.Mutex
+	inflightVAAs  map[string]struct{}
+	processedVAAs map[string]time.Time
+	dedupeTTL     time.Duration
+	// Durable delivery state; unfinished records are resumed on Start.
+	store      VAAStore
+	resumeVAAs []*VAARecord
+	// Guardian sets VAAs are verified against before delivery (nil disables verification).
+	guardians GuardianSetProvider
+	// Spy stream state and dependency checks served on /healthz and /readyz.
+	health *healthMonitor
+	// Routes an operator paused through the admin API; their VAAs are held as received.
+	pausedMu sync.RWMutex
+	paused   map[string]bool
+	// Next sequence to deliver per emitter on ordered routes, loaded from the store on first use.
+	sequencesMu   sync.Mutex
+	nextSequences map[string]uint64
+	// Highest contiguous sequence per emitter, and the API missing sequences are fetched from (nil only reports gaps).
+	gapsMu           sync.Mutex
+	sequenceTrackers map[string]*sequenceTracker
+	backfill         BackfillSource
+	// VAAs waiting for a delivery worker, and the processing context of the running Start loop.
+	queue       chan workItem
+	dispatchCtx context.Context
+	// Goroutines Start waits for on shutdown, so none of them outlives the store.
+	wg sync.WaitGroup
+}
+
+// New creates a new relayer instance. Dependencies not injected with opts are created
+// from the config: the VAA store is opened and the spy, EVM and Aztec clients are dialed.
+func New(config Config, opts ...Option) (*Relayer, error) {
+	o := options{logger: zap.NewNop()}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	logger := o.logger
+
+	relayer := &Relayer{
+		config:           config,
+		logger:           logger.With(zap.String("component", "Relayer")),
+		inflightVAAs:     make(map[string]struct{}),
+		processedVAAs:    make(map[string]time.Time),
+		dedupeTTL:        15 * time.Minute,
+		paused:           make(map[string]bool),
+		nextSequences:    make(map[string]uint64),
+		sequenceTrackers: make(map[string]*sequenceTracker),
+		queue:            make(chan workItem, max(config.Workers.QueueSize, 0)),
+	}
+
+	routes := config.RouteTable()
+	if err := validateRoutes(routes); err != nil {
+		return nil, fmt.Errorf("invalid route table: %v", err)
+	}
+	relayer.routes = routes
+	for _, route := range routes {
+		if len(route.Emitters) == 0 {
+			relayer.logger.Warn("Route has no allowlisted emitters and will not deliver anything", zap.String("route", route.Name))
+		}
+	}
+
+	// Open the VAA store and reload deliveries a previous run did not finish
+	store := o.vaaStore
+	if store == nil {
+		var err error
+		if store, err = openVAAStore(config); err != nil {
+			return nil, err
+		}
+	}
+	unfinished, err := store.List(VAAStatusReceived, VAAStatusSubmitted)
+	if err != nil {
+		store.Close()
+		return nil, fmt.Errorf("failed to load unfinished VAAs: %v", err)
+	}
+	if len(unfinished) > 0 {
+		relayer.logger.Info("Resuming unfinished VAA deliveries", zap.Int("count", len(unfinished)))
+	}
+	relayer.store = store
+	relayer.resumeVAAs = unfinished
+
+	// Connect to the spy service
+	spyClient := o.vaaSource
+	if spyClient == nil {
+		client, err := spy.NewClient(config.SpyRPCHost, logger)
+		if err != nil {
+			store.Close()
+			return nil, fmt.Errorf("failed to create spy client: %v", err)
+		}
+		spyClient = client
+	}
+
+	// Connect to each EVM destination chain without an injected submitter
+	evmClients := make(map[uint16]EVMSubmitter)
+	var signer evm.Signer // Shared by all chains, created once
+	var guardianRoute *Route
+	for i, route := range routes {
+		if route.Client != RouteClientEVM {
+			continue
+		}
+		if guardianRoute == nil {
+			guardianRoute = &routes[i]
+		}
+		if _, ok := evmClients[route.DestChainID]; ok {
+			continue
+		}
+		if submitter, ok := o.evmSubmitters[route.DestChainID]; ok {
+			evmClients[route.DestChainID] = submitter
+			continue
+		}
+		if signer == nil {
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			signer, err = NewSigner(ctx, config.Signer, logger)
+			cancel()
+			if err != nil {
+				spyClient.Close()
+				store.Close()
+				return nil, fmt.Errorf("failed to create EVM signer: %v", err)
+			}
+		}
+		evmClient, err := evm.NewClient(route.DestChainID, route.RPCURL, signer, config.EVM, logger)
+		if err != nil {
+			spyClient.Close()
+			closeSigner(signer)
+			store.Close()
+			return nil, fmt.Errorf("failed to create EVM client for chain %d: %v", route.DestChainID, err)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		err = evmClient.CheckNetworkID(ctx, route.networkID())
+		cancel()
+		if err != nil {
+			spyClient.Close()
+			closeSigner(signer)
+			store.Close()
+			return nil, fmt.Errorf("route %s: %v", route.Name, err)
+		}
+		evmClients[route.DestChainID] = evmClient
+	}
+
+	// Connect to Aztec via PXE and the verification service, if any route delivers there
+	var aztecClient AztecSubmitter
+	var verificationClient VerificationService
+	for _, route := range routes {
+		if route.Client != RouteClientAztec || aztecClient != nil {
+			continue
+		}
+		aztecClient = o.aztecSubmitter
+		if aztecClient == nil {
+			client, err := aztec.NewPXEClient(config.AztecPXEURL, config.AztecWalletAddress, logger)
+			if err != nil {
+				spyClient.Close()
+				closeSigner(signer)
+				store.Close()
+				return nil, fmt.Errorf("failed to create Aztec PXE client: %v", err)
+			}
+			aztecClient = client
+		}
+
+		// ADD: Create verification service client
+		verificationClient = o.verificationService
+		if verificationClient == nil {
+			verificationClient = verification.NewClient(config.VerificationServiceURL, logger)
+		}
+
+		// ADD: Test connection to verification service
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		if err := verificationClient.CheckHealth(ctx); err != nil {
+			relayer.logger.Warn("Verification service not available", zap.Error(err))
+			// Don't fail - the PXE fallback can still deliver
+		} else {
+			relayer.logger.Info("Connected to verification service", zap.String("url", config.VerificationServiceURL))
+		}
+		cancel()
+	}
+
+	// Load guardian sets used to check VAA signatures
+	guardians := o.guardianSetProvider
+	if guardians == nil {
+		guardians, err = newGuardianSetProvider(config, evmClients, guardianRoute, logger)
+		if err != nil {
+			spyClient.Close()
+			closeSigner(signer)
+			store.Close()
+			return nil, fmt.Errorf("failed to create guardian set provider: %v", err)
+		}
+	}
+	if guardians == nil {
+		relayer.logger.Warn("Guardian signature verification is disabled")
+	}
+
+	relayer.spyClient = spyClient
+	relayer.applySpyFilters()
+	relayer.aztecClient = aztecClient
+	relayer.evmClients = evmClients
+	relayer.signer = signer
+	relayer.verificationClient = verificationClient // ADD
+	relayer.guardians = guardians
+	relayer.backfill = o.backfillSource
+	if relayer.backfill == nil && config.Backfill.URL != "" {
+		relayer.backfill = wormholescan.NewClient(config.Backfill.URL, logger)
+	}
+	relayer.health = newHealthMonitor(relayer, config.Health, logger)
+
+	// Drop queued work the Treasury already has before anything is re-sent
+	relayer.reconcileDelivered()
+
+	// Set default VAA processor
+	relayer.vaaProcessor = o.vaaProcessor
+	if relayer.vaaProcessor == nil {
+		relayer.vaaProcessor = defaultVAAProcessor
+	}
+
+	return relayer, nil
+}
+
+// Close cleans up resources used by the relayer
+func (r *Relayer) Close() {
+	if r.spyClient != nil {
+		r.spyClient.Close()
+	}
+	closeSigner(r.signer)
+	if r.store != nil {
+		if err := r.store.Close(); err != nil {
+			r.logger.Warn("Failed to close VAA store", zap.Error(err))
+		}
+	}
+}
+
+// storePruneInterval is how often confirmed VAAs past the retention period are deleted
+var storePruneInterval = time.Hour
+
+// openVAAStore opens the bbolt file at StorePath. Without one, delivery state would not
+// survive a restart, so a store must be configured or injected with WithVAAStore.
+func openVAAStore(config Config) (VAAStore, error) {
+	if config.StorePath == "" {
+		return nil, errors.New("store path is required")
+	}
+	return NewBoltVAAStore(config.StorePath)
+}
+
+// runStorePruner deletes confirmed records once they are older than StoreRetention, so the
+// store and the scans over it do not grow with every VAA ever delivered
+func (r *Relayer) runStorePruner(ctx context.Context, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	ticker := time.NewTicker(storePruneInterval)
+	defer ticker.Stop()
+
+	for {
+		pruned, err := r.store.Prune(time.Now().Add(-r.config.StoreRetention))
+		if err != nil {
+			r.logger.Error("Failed to prune VAA store", zap.Error(err))
+		} else if pruned > 0 {
+			r.logger.Info("Pruned confirmed VAAs", zap.Int("count", pruned))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// newGuardianSetProvider returns the configured guardian set provider, or nil if verification
+// is disabled. The "contract" source reads the Wormhole core contract on the chain of route.
+func newGuardianSetProvider(config Config, evmClients map[uint16]EVMSubmitter, route *Route, logger *zap.Logger) (GuardianSetProvider, error) {
+	switch config.GuardianSetSource {
+	case "contract":
+		if route == nil {
+			return nil, fmt.Errorf("GUARDIAN_SET_SOURCE contract needs an EVM route")
+		}
+		caller, ok := evmClients[route.DestChainID].(ethereum.ContractCaller)
+		if !ok {
+			return nil, fmt.Errorf("GUARDIAN_SET_SOURCE contract needs an EVM client for chain %d that can call contracts", route.DestChainID)
+		}
+		return NewEVMGuardianSetProvider(caller, config.WormholeCoreContract, route.TargetContract, logger)
+	case "file":
+		if config.GuardianSetFile == "" {
+			return nil, fmt.Errorf("GUARDIAN_SET_FILE is required when GUARDIAN_SET_SOURCE is file")
+		}
+		return NewFileGuardianSetProvider(config.GuardianSetFile)
+	case "none":
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unknown guardian set source %q", config.GuardianSetSource)
+	}
+}
+
+// streamRetryDelay is how long Start waits before resubscribing after the VAA stream fails
+var streamRetryDelay = 5 * time.Second
+
+// Start begins listening for VAAs and processing them
+func (r *Relayer) Start(ctx context.Context) error {
+	r.logger.Info("Starting Wormhole relayer",
+		zap.Int("routes", len(r.currentRoutes())),
+		zap.String("verificationServiceURL", r.config.VerificationServiceURL)) // ADD
+
+	for _, route := range r.currentRoutes() {
+		fields := []zap.Field{
+			zap.String("route", route.Name),
+			zap.Uint16("sourceChain", route.SourceChainID),
+			zap.Uint16("destChain", route.DestChainID),
+			zap.String("client", route.Client),
+			zap.String("targetContract", route.TargetContract),
+			zap.Strings("emitters", route.Emitters.List()),
+			zap.Bool("ordered", route.Ordered),
+		}
+		if evmClient, err := r.evmClientFor(route); err == nil {
+			fields = append(fields, zap.String("relayerAddress", evmClient.GetAddress().Hex()))
+		} else if route.Client == RouteClientAztec {
+			fields = append(fields, zap.String("aztecWallet", r.aztecClient.GetWalletAddress()))
+		}
+		r.logger.Info("Route configured", fields...)
+
+		if len(route.Emitters) == 0 {
+			r.logger.Warn("No emitters allowlisted, the route's VAAs will be dropped", zap.String("route", route.Name))
+		}
+	}
+
+	// Track goroutines so shutdown waits for them
+	wg := &r.wg
+
+	// Create a separate context for graceful shutdown
+	processingCtx, cancelProcessing := context.WithCancel(context.Background())
+	defer cancelProcessing()
+	r.dispatchCtx = processingCtx
+
+	// Serve metrics before subscribing so a stalled spy is visible
+	if err := r.startOpsServer(ctx, wg); err != nil {
+		return err
+	}
+
+	// Subscribe to VAAs
+	stream, err := r.spyClient.SubscribeSignedVAA(ctx)
+	if err != nil {
+		return fmt.Errorf("subscribe to VAA stream: %v", err)
+	}
+
+	r.health.setSpyConnected(true)
+	r.logger.Info("Listening for VAAs")
+
+	// Deliver VAAs from the work queue with a fixed number of workers
+	r.startWorkers(processingCtx, wg)
+
+	// Resume deliveries interrupted by a previous shutdown or crash
+	for _, rec := range r.resumeVAAs {
+		if !r.beginProcessingVAA(rec.Key) {
+			continue
+		}
+		r.logger.Info("Resuming VAA delivery",
+			zap.String("vaaHash", rec.Key),
+			zap.String("status", string(rec.Status)),
+			zap.Uint64("sequence", rec.Sequence))
+		r.dispatchVAA(ctx, rec.RawBytes, rec.Key)
+	}
+	r.resumeVAAs = nil
+
+	// Re-attempt failed deliveries in the background
+	wg.Add(1)
+	go r.runRetryScheduler(ctx, processingCtx, wg)
+
+	// Replace EVM transactions that get stuck in the mempool
+	for _, evmClient := range r.evmClients {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			evmClient.MonitorTransactions(ctx)
+		}()
+	}
+
+	// Delete confirmed deliveries older than the retention period
+	if r.config.StoreRetention > 0 {
+		wg.Add(1)
+		go r.runStorePruner(ctx, wg)
+	}
+
+	// Check dependencies in the background for the health endpoints
+	wg.Add(1)
+	go r.health.run(ctx, wg)
+
+	for {
+		select {
+		case <-ctx.Done():
+			r.logger.Info("Shutting down relayer")
+			// Cancel all processing
+			cancelProcessing()
+			// Wait for all processing goroutines to complete
+			r.logger.Info("Waiting for all VAA processing to complete")
+			wg.Wait()
+			r.logger.Info("Shutdown complete")
+			return nil
+		default:
+			// Receive the next VAA
+			resp, err := stream.Recv()
+			if err != nil {
+				if ctx.Err() != nil {
+					// The stream ended because we are shutting down
+					continue
+				}
+				r.health.setSpyConnected(false)
+				if r.spyClient.FiltersChanged() {
+					r.logger.Info("Spy filters changed, resubscribing")
+				} else {
+					r.logger.Warn("Stream error, retrying", zap.Error(err), zap.Duration("retryIn", streamRetryDelay))
+					select {
+					case <-ctx.Done():
+						// Shut down through the loop rather than resubscribing
+						continue
+					case <-time.After(streamRetryDelay):
+					}
+				}
+				spyReconnectsTotal.Inc()
+				stream, err = r.spyClient.SubscribeSignedVAA(ctx)
+				if err != nil {
+					// Cancel all processing before returning
+					cancelProcessing()
+					// Wait for all processing goroutines to complete
+					wg.Wait()
+					return fmt.Errorf("subscribe to VAA stream after retry: %v", err)
+				}
+				r.health.setSpyConnected(true)
+				continue
+			}
+
+			r.health.markVAA()
+			routeName := r.routeNameOfVAA(resp.VaaBytes)
+			vaasReceivedTotal.WithLabelValues(routeName).Inc()
+
+			key := computeVAAKey(resp.VaaBytes)
+			if !r.beginProcessingVAA(key) {
+				vaasDedupedTotal.WithLabelValues(routeName).Inc()
+				r.logger.Debug("Skipping duplicate VAA", zap.String("vaaHash", key))
+				continue
+			}
+
+			// Blocks while the work queue is full, pausing reads from the spy
+			r.dispatchVAA(ctx, resp.VaaBytes, key)
+		}
+	}
+}
+
+func (r *Relayer) processVAA(ctx context.Context, key string, vaaBytes []byte) error {
+	vaasInFlight.Inc()
+	defer vaasInFlight.Dec()
+
+	// Check for context cancellation first
+	select {
+	case <-ctx.Done():
+		r.logger.Debug("Processing cancelled for VAA")
+		return ctx.Err()
+	default:
+		// Continue processing
+	}
+
+	// Parse the VAA
+	wormholeVAA, err := vaaLib.Unmarshal(vaaBytes)
+	if err != nil {
+		r.logger.Error("Failed to parse VAA", zap.Error(err))
+		return err
+	}
+
+	// Extract the txID from the payload (first 32 bytes)
+	txID := ""
+	if len(wormholeVAA.Payload) >= 32 {
+		txIDBytes := wormholeVAA.Payload[:32]
+		txID = fmt.Sprintf("0x%x", txIDBytes)
+		r.logger.Debug("Extracted txID from payload", zap.String("txID", txID))
+	} else {
+		r.logger.Debug("Payload too short to contain txID", zap.Int("payload_length", len(wormholeVAA.Payload)))
+	}
+
+	// Create VAA data with essential information
+	vaaData := &VAAData{
+		VAA:        wormholeVAA,
+		RawBytes:   vaaBytes,
+		ChainID:    uint16(wormholeVAA.EmitterChain),
+		EmitterHex: wormholeVAA.EmitterAddress.String(),
+		Sequence:   wormholeVAA.Sequence,
+		TxID:       txID,
+		Key:        key,
+	}
+
+	r.logger.Debug("Processing VAA",
+		zap.Uint16("chain", vaaData.ChainID),
+		zap.Uint64("sequence", vaaData.Sequence),
+		zap.String("emitter", vaaData.EmitterHex),
+		zap.String("sourceTxID", vaaData.TxID))
+
+	// Use the passed context when calling the processor
+	if err := r.vaaProcessor(r, vaaData); err != nil {
+		if errors.Is(err, errRoutePaused) {
+			r.logger.Info("Holding VAA while delivery is paused", zap.String("vaaHash", key), zap.Uint64("sequence", vaaData.Sequence))
+			return err
+		}
+		if errors.Is(err, errSequenceHeld) {
+			return err
+		}
+		r.logger.Error("Error processing VAA", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// routeNameOfVAA returns the route name of raw VAA bytes, for metric labels
+func (r *Relayer) routeNameOfVAA(vaaBytes []byte) string {
+	v, err := vaaLib.Unmarshal(vaaBytes)
+	if err != nil {
+		return routeUnmatched
+	}
+	route, reason := r.matchRoute(uint16(v.EmitterChain), v.EmitterAddress.String(), v.Payload)
+	if reason != "" {
+		return routeUnmatched
+	}
+	return route.Name
+}
+
+func (r *Relayer) beginProcessingVAA(key string) bool {
+	r.dedupeMu.Lock()
+	defer r.dedupeMu.Unlock()
+
+	// Drop if we already processed this VAA recently—spy service can replay messages.
+	if ts, ok := r.processedVAAs[key]; ok {
+		if time.Since(ts) < r.dedupeTTL {
+			return false
+		}
+		delete(r.processedVAAs, key)
+	}
+
+	// Another goroutine is already working on this VAA; let it finish.
+	if _, ok := r.inflightVAAs[key]; ok {
+		return false
+	}
+
+	if rec, err := r.store.Get(key); err == nil {
+		switch rec.Status {
+		case VAAStatusConfirmed:
+			// Delivered before the TTL window or before a restart.
+			r.processedVAAs[key] = time.Now()
+			return false
+		case VAAStatusDeadLettered:
+			// Retries exhausted; only an operator requeue brings it back.
+			return false
+		}
+	}
+
+	r.inflightVAAs[key] = struct{}{}
+	return true
+}
+
+func (r *Relayer) finishProcessingVAA(key string, procErr error) {
+	success := procErr == nil
+	var finished VAARecord // Set once the VAA is confirmed or dead-lettered
+
+	switch {
+	case success:
+		r.updateRecord(key, func(rec *VAARecord) {
+			rec.Status = VAAStatusConfirmed
+			rec.LastError = ""
+			finished = *rec
+		})
+	case errors.Is(procErr, context.Canceled):
+		// Interrupted by shutdown; leave the record as is so it is resumed on restart.
+	case errors.Is(procErr, errRoutePaused):
+		// Held until an operator resumes the route; not a failed attempt.
+	case errors.Is(procErr, errSequenceHeld):
+		// Held until the earlier sequences of its emitter are finished; not a failed attempt.
+	default:
+		r.updateRecord(key, func(rec *VAARecord) {
+			rec.Attempts++
+			rec.LastError = procErr.Error()
+
+			if isPermanent(procErr) {
+				var sigErr *signatureError
+				rec.Status = VAAStatusDeadLettered
+				rec.SignatureRejected = errors.As(procErr, &sigErr)
+				finished = *rec
+				r.logger.Error("VAA rejected, moved to dead-letter queue",
+					zap.String("vaaHash", key),
+					zap.Uint64("sequence", rec.Sequence),
+					zap.Error(procErr))
+				return
+			}
+
+			if r.config.RetryPolicy.Exhausted(rec.Attempts) {
+				rec.Status = VAAStatusDeadLettered
+				finished = *rec
+				r.logger.Error("VAA delivery retries exhausted, moved to dead-letter queue",
+					zap.String("vaaHash", key),
+					zap.Uint64("sequence", rec.Sequence),
+					zap.Int("attempts", rec.Attempts),
+					zap.Error(procErr))
+				return
+			}
+
+			rec.Status = VAAStatusFailed
+			rec.NextAttemptAt = time.Now().Add(r.config.RetryPolicy.Backoff(rec.Attempts))
+			r.logger.Warn("VAA delivery failed, scheduled retry",
+				zap.String("vaaHash", key),
+				zap.Uint64("sequence", rec.Sequence),
+				zap.Int("attempts", rec.Attempts),
+				zap.Time("nextAttemptAt", rec.NextAttemptAt))
+		})
+	}
+
+	r.dedupeMu.Lock()
+	delete(r.inflightVAAs, key)
+
+	if success {
+		// Cache the completion timestamp so replays are ignored within the TTL window.
+		r.processedVAAs[key] = time.Now()
+	}
+
+	cutoff := time.Now().Add(-r.dedupeTTL)
+	for k, ts := range r.processedVAAs {
+		if ts.Before(cutoff) {
+			delete(r.processedVAAs, k)
+		}
+	}
+	r.dedupeMu.Unlock()
+
+	// On ordered routes, a finished sequence lets the next one through. A VAA that failed
+	// guardian verification does not count: the genuine VAA with its sequence may still come.
+	if finished.Key != "" && !finished.SignatureRejected {
+		r.advanceSequence(finished.ChainID, finished.EmitterHex, finished.Sequence)
+	} else if errors.Is(procErr, errSequenceHeld) {
+		r.recheckHeld(key)
+	}
+}
+
+// reconcileDelivered marks persisted VAAs on EVM routes that the Treasury has already
+// processed as confirmed, so they are neither resumed nor retried
+func (r *Relayer) reconcileDelivered() {
+	records, err := r.store.List(VAAStatusReceived, VAAStatusSubmitted, VAAStatusFailed, VAAStatusDeadLettered)
+	if err != nil {
+		r.logger.Error("Failed to list VAAs for reconciliation", zap.Error(err))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	delivered := make(map[string]bool)
+	for _, rec := range records {
+		route, reason := r.matchRecordRoute(rec)
+		if reason != "" || route.Client != RouteClientEVM {
+			continue
+		}
+		evmClient, err := r.evmClientFor(route)
+		if err != nil {
+			continue
+		}
+		emitter, err := vaaLib.StringToAddress(rec.EmitterHex)
+		if err != nil {
+			continue
+		}
+
+		processed, err := evmClient.IsMessageProcessed(ctx, route.TargetContract, rec.ChainID, emitter, rec.Sequence)
+		if err != nil {
+			r.logger.Warn("Failed to reconcile VAA with Treasury", zap.String("vaaHash", rec.Key), zap.Error(err))
+			continue
+		}
+		if !processed {
+			continue
+		}
+
+		r.logger.Info("Persisted VAA already processed by Treasury, marking confirmed",
+			zap.String("vaaHash", rec.Key),
+			zap.String("status", string(rec.Status)),
+			zap.Uint64("sequence", rec.Sequence))
+		r.updateRecord(rec.Key, func(rec *VAARecord) {
+			rec.Status = VAAStatusConfirmed
+			rec.LastError = ""
+		})
+		delivered[rec.Key] = true
+	}
+
+	resume := r.resumeVAAs[:0]
+	for _, rec := range r.resumeVAAs {
+		if !delivered[rec.Key] {
+			resume = append(resume, rec)
+		}
+	}
+	r.resumeVAAs = resume
+}
+
+// errRecordNotTracked aborts a store update for a VAA the processor never recorded
+var errRecordNotTracked = errors.New("vaa not tracked")
+
+// recordReceived persists a VAA that is queued or about to be delivered, keeping any earlier state
+func (r *Relayer) recordReceived(vaaData *VAAData) error {
+	err := r.store.Update(vaaData.Key, func(rec *VAARecord) error {
+		rec.RawBytes = vaaData.RawBytes
+		rec.ChainID = vaaData.ChainID
+		rec.EmitterHex = vaaData.EmitterHex
+		rec.Sequence = vaaData.Sequence
+		if rec.Status == "" || rec.Status == VAAStatusFailed {
+			rec.Status = VAAStatusReceived
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to persist VAA: %v", err)
+	}
+	return nil
+}
+
+// recordSubmitted persists the delivery transaction hash for a VAA
+func (r *Relayer) recordSubmitted(key, txHash string) {
+	r.updateRecord(key, func(rec *VAARecord) {
+		rec.Status = VAAStatusSubmitted
+		rec.TxHashes = append(rec.TxHashes, txHash)
+	})
+}
+
+// updateRecord applies fn to a tracked VAA record; VAAs that were never recorded are ignored
+func (r *Relayer) updateRecord(key string, fn func(rec *VAARecord)) {
+	err := r.store.Update(key, func(rec *VAARecord) error {
+		if rec.CreatedAt.IsZero() {
+			return errRecordNotTracked
+		}
+		fn(rec)
+		return nil
+	})
+	if err != nil && !errors.Is(err, errRecordNotTracked) {
+		r.logger.Error("Failed to update VAA record", zap.String("vaaHash", key), zap.Error(err))
+	}
+}
+
+func computeVAAKey(vaaBytes []byte) string {
+	hash := sha256.Sum256(vaaBytes)
+	return hex.EncodeToString(hash[:])
+}
+
+// defaultVAAProcessor delivers the VAA over the route its emitter is allowlisted on, picking
+// between EVM routes by the target chain in the payload
+func defaultVAAProcessor(r *Relayer, vaaData *VAAData) error {
+	// Create a context with timeout for processing operations, leaving room to wait for EVM receipts.
+	// It ends with the Start loop's processing context, so shutdown interrupts the delivery.
+	parent := r.dispatchCtx
+	if parent == nil {
+		parent = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(parent, 60*time.Second+r.config.EVM.ReceiptTimeout) // Increased timeout for HTTP calls
+	defer cancel()
+
+	// Log essential VAA information at debug level
+	r.logger.Debug("VAA Details",
+		zap.Uint16("emitterChain", vaaData.ChainID),
+		zap.String("emitterAddress", vaaData.EmitterHex),
+		zap.Uint64("sequence", vaaData.Sequence),
+		zap.Time("timestamp", vaaData.VAA.Timestamp),
+		zap.Int("payloadLength", len(vaaData.VAA.Payload)),
+		zap.String("sourceTxID", vaaData.TxID))
+
+	// Extract and log key payload information at debug level
+	r.logger.Debug("VAA Payload", zap.String("payloadHex", fmt.Sprintf("%x", vaaData.VAA.Payload)))
+
+	route, reason := r.matchRoute(vaaData.ChainID, vaaData.EmitterHex, vaaData.VAA.Payload)
+	if reason == "target_chain" {
+		return r.rejectUnknownTargetChain(vaaData)
+	}
+	if reason != "" {
+		// Skip VAAs no route delivers
+		vaasFilteredTotal.WithLabelValues(routeUnmatched, reason).Inc()
+		r.logger.Debug("Skipping VAA that matches no route",
+			zap.String("reason", reason),
+			zap.Uint16("chain", vaaData.ChainID),
+			zap.String("emitter", vaaData.EmitterHex),
+			zap.Uint64("sequence", vaaData.Sequence))
+		return nil
+	}
+
+	r.logger.Info("Processing VAA",
+		zap.String("route", route.Name),
+		zap.Uint16("destChain", route.DestChainID),
+		zap.Uint64("sequence", vaaData.Sequence),
+		zap.String("sourceTxID", vaaData.TxID))
+
+	if route.Client == RouteClientEVM {
+		// Only well-formed MultiSig transfers are sent to a Treasury
+		transfer, decodeErr := payload.Decode(vaaData.VAA.Payload)
+		if decodeErr != nil {
+			vaasFilteredTotal.WithLabelValues(route.Name, "payload").Inc()
+			r.logger.Error("Rejecting VAA with invalid MultiSig payload",
+				zap.String("route", route.Name),
+				zap.Uint64("sequence", vaaData.Sequence),
+				zap.Error(decodeErr))
+			// Keep a record so the rejection shows up in the dead-letter queue
+			if err := r.recordReceived(vaaData); err != nil {
+				return err
+			}
+			return permanent(fmt.Errorf("invalid MultiSig payload: %v", decodeErr))
+		}
+		r.logger.Debug("MultiSig transfer payload",
+			zap.String("txID", fmt.Sprintf("0x%x", transfer.TxID)),
+			zap.Uint16("targetChain", transfer.TargetChain),
+			zap.String("token", transfer.Token.Hex()),
+			zap.String("recipient", transfer.Recipient.Hex()),
+			zap.String("amount", transfer.Amount.String()))
+	}
+
+	if err := r.recordReceived(vaaData); err != nil {
+		return err
+	}
+	r.observeSequence(ctx, route, vaaData)
+	if r.IsPaused(route.Name) {
+		return errRoutePaused
+	}
+	// Destinations trust the relayer to forward only guardian-signed VAAs. Checked before the
+	// ordering gate, so a forged VAA cannot take the turn of the sequence it claims.
+	if err := r.checkGuardianSignatures(ctx, route.Name, vaaData); err != nil {
+		return err
+	}
+	if route.Ordered {
+		if err := r.awaitTurn(route, vaaData); err != nil {
+			return err
+		}
+	}
+
+	var txHash string
+	var err error
+	switch route.Client {
+	case RouteClientEVM:
+		// Only a mined and successful verify() counts as delivered
+		txHash, err = r.deliverToEVM(ctx, route, vaaData)
+	case RouteClientAztec:
+		txHash, err = r.deliverToAztec(ctx, route, vaaData)
+	default:
+		err = fmt.Errorf("route %s has unknown client %q", route.Name, route.Client)
+	}
+
+	if err != nil {
+		vaasFailedTotal.WithLabelValues(route.Name).Inc()
+
+		// Check if the context was cancelled or timed out
+		if ctx.Err() != nil {
+			r.logger.Warn("Transaction sending cancelled or timed out", zap.Error(ctx.Err()))
+			return fmt.Errorf("transaction interrupted: %w", ctx.Err())
+		}
+
+		r.logger.Error("Failed to send verify transaction",
+			zap.String("route", route.Name),
+			zap.Uint64("sequence", vaaData.Sequence),
+			zap.String("sourceTxID", vaaData.TxID),
+			zap.Error(err))
+		return fmt.Errorf("transaction failed: %w", err)
+	}
+
+	// An empty hash means the destination already had the VAA and nothing was sent
+	if txHash != "" {
+		observeDelivery(route.Name, vaaData.VAA.Timestamp)
+	}
+
+	r.logger.Info("VAA verification completed",
+		zap.String("route", route.Name),
+		zap.Uint64("sequence", vaaData.Sequence),
+		zap.String("txHash", txHash),
+		zap.String("sourceTxID", vaaData.TxID))
+
+	return nil
+}
+
+// rejectUnknownTargetChain dead-letters a transfer whose payload asks for a payout on a chain
+// no route from its emitter delivers to. Sending it anywhere else would pay out on the wrong chain.
+func (r *Relayer) rejectUnknownTargetChain(vaaData *VAAData) error {
+	transfer, err := payload.Decode(vaaData.VAA.Payload)
+	if err != nil {
+		return permanent(fmt.Errorf("invalid MultiSig payload: %v", err))
+	}
+	err = permanent(fmt.Errorf("no route from chain %d emitter %s pays out on target chain %d",
+		vaaData.ChainID, vaaData.EmitterHex, transfer.TargetChain))
+
+	vaasFilteredTotal.WithLabelValues(routeUnmatched, "target_chain").Inc()
+	r.logger.Error("Rejecting VAA for unknown target chain",
+		zap.Uint16("chain", vaaData.ChainID),
+		zap.String("emitter", vaaData.EmitterHex),
+		zap.Uint64("sequence", vaaData.Sequence),
+		zap.Uint16("targetChain", transfer.TargetChain),
+		zap.String("sourceTxID", vaaData.TxID))
+
+	// Keep a record so the rejection shows up in the dead-letter queue
+	if recErr := r.recordReceived(vaaData); recErr != nil {
+		return recErr
+	}
+	return err
+}
+
+// deliverToAztec submits the VAA through the verification service, falling back to the PXE
+func (r *Relayer) deliverToAztec(ctx context.Context, route Route, vaaData *VAAData) (string, error) {
+	if r.aztecClient == nil {
+		return "", fmt.Errorf("no Aztec client configured")
+	}
+
+	// MODIFY: Try verification service first, fallback to direct PXE
+	txHash, err := r.verificationClient.VerifyVAA(ctx, vaaData.RawBytes)
+	observeAztecSubmission("verification_service", err)
+	if err != nil {
+		r.logger.Warn("Verification service failed, trying direct PXE", zap.Error(err))
+		// Fallback to direct PXE call
+		txHash, err = r.aztecClient.SendVerifyTransaction(ctx, route.TargetContract, vaaData.RawBytes)
+		observeAztecSubmission("pxe_fallback", err)
+	} else {
+		r.logger.Debug("Used verification service successfully")
+	}
+	if err != nil {
+		return "", err
+	}
+	r.recordSubmitted(vaaData.Key, txHash)
+	return txHash, nil
+}
+
+// signatureError is a permanent error for a VAA that failed guardian verification. Unlike other
+// dead-lettered VAAs, it may be forged, so it never moves an ordered emitter past its sequence.
+type signatureError struct {
+	err error
+}
+
+func (e *signatureError) Error() string { return e.err.Error() }
+func (e *signatureError) Unwrap() error { return e.err }
+
+// checkGuardianSignatures verifies the VAA against the guardian set before anything is sent
+func (r *Relayer) checkGuardianSignatures(ctx context.Context, routeName string, vaaData *VAAData) error {
+	if r.guardians == nil {
+		return nil
+	}
+
+	err := verifyGuardianSignatures(ctx, r.guardians, vaaData.VAA)
+	if err == nil {
+		return nil
+	}
+	if !isPermanent(err) {
+		r.logger.Warn("Could not check guardian signatures yet, will retry", zap.Error(err))
+		return err
+	}
+
+	vaasFilteredTotal.WithLabelValues(routeName, "signature").Inc()
+	r.logger.Error("Rejecting VAA that failed guardian verification",
+		zap.String("route", routeName),
+		zap.Uint16("chain", vaaData.ChainID),
+		zap.String("emitter", vaaData.EmitterHex),
+		zap.Uint64("sequence", vaaData.Sequence),
+		zap.Uint32("guardianSetIndex", vaaData.VAA.GuardianSetIndex),
+		zap.Error(err))
+	return &signatureError{err: err}
+}
+
+// deliverToEVM sends verify() for the VAA and waits for its receipt. A transaction left
+// in flight by a previous run is awaited first instead of being sent again.
+func (r *Relayer) deliverToEVM(ctx context.Context, route Route, vaaData *VAAData) (string, error) {
+	evmClient, err := r.evmClientFor(route)
+	if err != nil {
+		return "", err
+	}
+
+	// Skip VAAs the Treasury has already processed instead of paying for a revert
+	processed, err := evmClient.IsMessageProcessed(ctx, route.TargetContract,
+		vaaData.ChainID, vaaData.VAA.EmitterAddress, vaaData.Sequence)
+	if err != nil {
+		r.logger.Warn("Pre-flight isMessageProcessed check failed, delivering anyway", zap.Error(err))
+	} else if processed {
+		r.logger.Info("VAA already processed by Treasury, not sending",
+			zap.Uint64("sequence", vaaData.Sequence),
+			zap.String("sourceTxID", vaaData.TxID))
+		return "", nil
+	}
+
+	if txHash := r.pendingTxHash(vaaData.Key); txHash != "" {
+		r.logger.Info("Waiting for previously submitted transaction",
+			zap.Uint64("sequence", vaaData.Sequence),
+			zap.String("txHash", txHash))
+
+		receipt, err := evmClient.WaitForReceipt(ctx, txHash)
+		if receipt != nil {
+			return r.recordMined(vaaData.Key, txHash, receipt), err
+		}
+		if ctx.Err() != nil {
+			return txHash, err
+		}
+		r.logger.Warn("Previously submitted transaction not confirmed, sending again",
+			zap.String("txHash", txHash),
+			zap.Error(err))
+	}
+
+	txHash, err := evmClient.SendVerifyTransaction(ctx, route.TargetContract, vaaData.RawBytes)
+	if err != nil {
+		return "", err
+	}
+	r.recordEVMSubmitted(vaaData.Key, txHash)
+
+	receipt, err := evmClient.WaitForReceipt(ctx, txHash)
+	if receipt != nil {
+		return r.recordMined(vaaData.Key, txHash, receipt), err
+	}
+	return txHash, err
+}
+
+// recordEVMSubmitted persists an EVM delivery transaction, which stays pending until a receipt
+// for it is seen, so a retry after a failed attempt waits for it instead of sending another
+func (r *Relayer) recordEVMSubmitted(key, txHash string) {
+	r.updateRecord(key, func(rec *VAARecord) {
+		rec.Status = VAAStatusSubmitted
+		rec.TxHashes = append(rec.TxHashes, txHash)
+		rec.PendingTxHash = txHash
+	})
+}
+
+// recordMined clears the pending transaction once it, or a fee-bumped replacement, is mined,
+// successfully or not, and returns the hash of the mined transaction
+func (r *Relayer) recordMined(key, txHash string, receipt *types.Receipt) string {
+	minedHash := receipt.TxHash.Hex()
+	r.updateRecord(key, func(rec *VAARecord) {
+		if minedHash != txHash {
+			rec.TxHashes = append(rec.TxHashes, minedHash)
+		}
+		rec.PendingTxHash = ""
+	})
+	return minedHash
+}
+
+// pendingTxHash returns the latest EVM delivery transaction of a VAA not yet seen mined. It
+// survives failed attempts, so a retry does not send a second transaction next to it.
+func (r *Relayer) pendingTxHash(key string) string {
+	rec, err := r.store.Get(key)
+	if err != nil {
+		return ""
+	}
+	if rec.PendingTxHash == "" && rec.Status == VAAStatusSubmitted && len(rec.TxHashes) > 0 {
+		return rec.TxHashes[len(rec.TxHashes)-1] // Recorded before pending transactions were tracked
+	}
+	return rec.PendingTxHash
+}
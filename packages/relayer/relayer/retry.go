@@ -0,0 +1,167 @@
+package relayer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wormhole-foundation/wormhole/aztec/relayer/evm"
+	"go.uber.org/zap"
+)
+
+// retryPollInterval is how often the scheduler looks for failed VAAs that are due for another attempt
+var retryPollInterval = 5 * time.Second
+
+// RetryPolicy controls how failed deliveries are re-attempted before being dead-lettered
+type RetryPolicy struct {
+	MaxAttempts    int           // Attempts before a VAA is moved to the dead-letter queue
+	InitialBackoff time.Duration // Delay before the first retry
+	MaxBackoff     time.Duration // Upper bound for the delay between retries
+	Multiplier     float64       // Backoff growth factor per attempt
+	Jitter         float64       // Random +/- fraction applied to each delay (0.2 = 20%)
+}
+
+// Backoff returns the delay before the next attempt, given how many attempts have failed so far
+func (p RetryPolicy) Backoff(failedAttempts int) time.Duration {
+	if failedAttempts < 1 {
+		failedAttempts = 1
+	}
+
+	delay := float64(p.InitialBackoff) * math.Pow(p.Multiplier, float64(failedAttempts-1))
+	if p.MaxBackoff > 0 && delay > float64(p.MaxBackoff) {
+		delay = float64(p.MaxBackoff)
+	}
+	if p.Jitter > 0 {
+		delay *= 1 + p.Jitter*(2*rand.Float64()-1)
+	}
+	return time.Duration(delay)
+}
+
+// Exhausted reports whether a VAA with this many failed attempts should be dead-lettered
+func (p RetryPolicy) Exhausted(failedAttempts int) bool {
+	return p.MaxAttempts > 0 && failedAttempts >= p.MaxAttempts
+}
+
+// permanentError marks a delivery failure that retrying cannot fix
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// permanent wraps err so the VAA is dead-lettered without further retries
+func permanent(err error) error {
+	return &permanentError{err: err}
+}
+
+// permanentRevertReasons are verify() revert reasons no retry can change: the Treasury already
+// processed the VAA, or the Wormhole core contract rejects it for good. Other reverts, such as
+// an underfunded Treasury or a paused token, may pass later and are retried.
+var permanentRevertReasons = []string{
+	"Message already processed",
+	"Payload too short",
+	"VM signature invalid",
+	"VM version incompatible",
+	"no quorum",
+	"guardian set has expired",
+	"guardian index out of bounds",
+	"signature indices must be ascending",
+}
+
+// isPermanent reports whether err was marked as permanent. Cancelled EVM transactions are
+// permanent too: the operator stopped the delivery on purpose. So are verify() calls that gas
+// estimation showed would revert with one of the permanentRevertReasons.
+func isPermanent(err error) bool {
+	var perm *permanentError
+	var cancelled *evm.CancelledError
+	if errors.As(err, &perm) || errors.As(err, &cancelled) {
+		return true
+	}
+	var likelyRevert *evm.LikelyRevertError
+	if errors.As(err, &likelyRevert) {
+		for _, reason := range permanentRevertReasons {
+			if strings.Contains(likelyRevert.Reason, reason) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// runRetryScheduler re-dispatches failed VAAs once their backoff has elapsed
+func (r *Relayer) runRetryScheduler(ctx, processingCtx context.Context, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	ticker := time.NewTicker(retryPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		failed, err := r.store.List(VAAStatusFailed)
+		if err != nil {
+			r.logger.Error("Failed to list VAAs for retry", zap.Error(err))
+			continue
+		}
+
+		now := time.Now()
+		for _, rec := range failed {
+			if rec.NextAttemptAt.After(now) || r.IsPaused(r.routeNameOf(rec)) {
+				continue
+			}
+			if !r.beginProcessingVAA(rec.Key) {
+				continue
+			}
+
+			r.logger.Info("Retrying VAA delivery",
+				zap.String("vaaHash", rec.Key),
+				zap.Uint64("sequence", rec.Sequence),
+				zap.Int("attempt", rec.Attempts+1),
+				zap.String("lastError", rec.LastError))
+			r.dispatchVAA(processingCtx, rec.RawBytes, rec.Key)
+		}
+	}
+}
+
+// DeadLetteredVAAs lists VAAs whose retries were exhausted
+func (r *Relayer) DeadLetteredVAAs() ([]*VAARecord, error) {
+	return r.store.List(VAAStatusDeadLettered)
+}
+
+// RequeueVAA moves a dead-lettered VAA back into the retry queue with a fresh attempt budget
+func (r *Relayer) RequeueVAA(key string) error {
+	if err := RequeueDeadLettered(r.store, key); err != nil {
+		return err
+	}
+	r.logger.Info("Requeued dead-lettered VAA", zap.String("vaaHash", key))
+	return nil
+}
+
+// RequeueDeadLettered moves a dead-lettered VAA in store back into the retry queue. Use it
+// on the store of a stopped relayer; a running one holds the store lock.
+func RequeueDeadLettered(store VAAStore, key string) error {
+	if _, err := store.Get(key); err != nil {
+		return err
+	}
+
+	return store.Update(key, func(rec *VAARecord) error {
+		if rec.Status != VAAStatusDeadLettered {
+			return fmt.Errorf("vaa %s is %s, not %s", key, rec.Status, VAAStatusDeadLettered)
+		}
+		rec.Status = VAAStatusFailed
+		rec.Attempts = 0
+		rec.NextAttemptAt = time.Time{}
+		rec.SignatureRejected = false
+		return nil
+	})
+}
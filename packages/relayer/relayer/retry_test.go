@@ -0,0 +1,200 @@
+package relayer
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/wormhole-foundation/wormhole/aztec/relayer/evm"
+)
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{InitialBackoff: time.Second, MaxBackoff: 10 * time.Second, Multiplier: 2}
+	tests := []struct {
+		failed int
+		want   time.Duration
+	}{
+		{0, time.Second},
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{4, 8 * time.Second},
+		{5, 10 * time.Second}, // Capped
+		{50, 10 * time.Second},
+	}
+	for _, tt := range tests {
+		if got := policy.Backoff(tt.failed); got != tt.want {
+			t.Errorf("Backoff(%d) = %s, want %s", tt.failed, got, tt.want)
+		}
+	}
+
+	policy.Jitter = 0.2
+	for i := 0; i < 100; i++ {
+		if got := policy.Backoff(2); got < 1600*time.Millisecond || got > 2400*time.Millisecond {
+			t.Fatalf("Backoff(2) with 20%% jitter = %s, want within 20%% of 2s", got)
+		}
+	}
+}
+
+func TestRetryPolicyExhausted(t *testing.T) {
+	tests := []struct {
+		maxAttempts int
+		failed      int
+		want        bool
+	}{
+		{3, 2, false},
+		{3, 3, true},
+		{3, 4, true},
+		{0, 1000, false}, // Unlimited
+	}
+	for _, tt := range tests {
+		if got := (RetryPolicy{MaxAttempts: tt.maxAttempts}).Exhausted(tt.failed); got != tt.want {
+			t.Errorf("Exhausted(%d) with %d max attempts = %v, want %v", tt.failed, tt.maxAttempts, got, tt.want)
+		}
+	}
+}
+
+func TestIsPermanent(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"transient", errors.New("rpc unavailable"), false},
+		{"permanent", permanent(errors.New("bad payload")), true},
+		{"wrapped permanent", fmt.Errorf("deliver: %w", permanent(errors.New("bad payload"))), true},
+		{"cancelled transaction", fmt.Errorf("wait: %w", &evm.CancelledError{TxHash: "0x1", CancelHash: "0x2"}), true},
+		{"already processed", fmt.Errorf("failed to send transaction: %w", &evm.LikelyRevertError{Reason: "Message already processed"}), true},
+		{"invalid VAA", &evm.LikelyRevertError{Reason: "execution reverted: VM signature invalid"}, true},
+		{"underfunded treasury", &evm.LikelyRevertError{Reason: "ERC20: transfer amount exceeds balance"}, false},
+		{"paused token", &evm.LikelyRevertError{Reason: "Pausable: paused"}, false},
+		{"unknown revert", &evm.LikelyRevertError{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isPermanent(tt.err); got != tt.want {
+				t.Errorf("isPermanent(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+// scriptedProcessor records each VAA like the default processor and fails with err until it is cleared
+type scriptedProcessor struct {
+	mu    sync.Mutex
+	err   error
+	calls int
+}
+
+func (p *scriptedProcessor) fail(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.err = err
+}
+
+func (p *scriptedProcessor) count() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.calls
+}
+
+func (p *scriptedProcessor) process(r *Relayer, vaaData *VAAData) error {
+	if err := r.recordReceived(vaaData); err != nil {
+		return err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.calls++
+	return p.err
+}
+
+func startScriptedRun(t *testing.T, p *scriptedProcessor) *fakeRun {
+	return startFakeRun(t, func(fc *fileConfig) {
+		fc.Retry.MaxAttempts = 3
+		fc.Retry.InitialBackoff = 10 * time.Millisecond
+	}, WithVAAProcessor(p.process))
+}
+
+func TestRetrySchedulerDeadLettersAfterMaxAttempts(t *testing.T) {
+	p := &scriptedProcessor{err: errors.New("rpc unavailable")}
+	f := startScriptedRun(t, p)
+
+	vaaBytes := f.aztecVAA()
+	f.source.Publish(vaaBytes)
+	rec := f.waitForStatus(vaaBytes, VAAStatusDeadLettered)
+	if rec.Attempts != 3 || p.count() != 3 {
+		t.Errorf("dead-lettered after %d recorded attempts and %d deliveries, want 3", rec.Attempts, p.count())
+	}
+	if rec.LastError != "rpc unavailable" {
+		t.Errorf("LastError = %q, want the delivery error", rec.LastError)
+	}
+}
+
+func TestRetrySchedulerRetriesAfterBackoff(t *testing.T) {
+	p := &scriptedProcessor{err: errors.New("rpc unavailable")}
+	f := startScriptedRun(t, p)
+
+	vaaBytes := f.aztecVAA()
+	f.source.Publish(vaaBytes)
+	rec := f.waitForStatus(vaaBytes, VAAStatusFailed)
+	if rec.NextAttemptAt.Before(rec.UpdatedAt) {
+		t.Errorf("NextAttemptAt %s is before the failure at %s", rec.NextAttemptAt, rec.UpdatedAt)
+	}
+
+	p.fail(nil)
+	rec = f.waitForStatus(vaaBytes, VAAStatusConfirmed)
+	if rec.LastError != "" {
+		t.Errorf("confirmed record kept LastError %q", rec.LastError)
+	}
+}
+
+func TestPermanentFailureIsDeadLetteredAndRequeued(t *testing.T) {
+	p := &scriptedProcessor{err: permanent(errors.New("bad payload"))}
+	f := startScriptedRun(t, p)
+
+	vaaBytes := f.aztecVAA()
+	f.source.Publish(vaaBytes)
+	rec := f.waitForStatus(vaaBytes, VAAStatusDeadLettered)
+	if rec.Attempts != 1 {
+		t.Errorf("permanent failure dead-lettered after %d attempts, want 1", rec.Attempts)
+	}
+	if dead, _ := f.relayer.DeadLetteredVAAs(); len(dead) != 1 || dead[0].Key != rec.Key {
+		t.Errorf("DeadLetteredVAAs returned %d records, want the rejected VAA", len(dead))
+	}
+
+	// Replays of a dead-lettered VAA are not retried
+	f.source.Publish(vaaBytes)
+	time.Sleep(100 * time.Millisecond)
+	if n := p.count(); n != 1 {
+		t.Fatalf("processor ran %d times for a dead-lettered VAA, want 1", n)
+	}
+
+	p.fail(nil)
+	if err := f.relayer.RequeueVAA(rec.Key); err != nil {
+		t.Fatalf("RequeueVAA: %v", err)
+	}
+	f.waitForStatus(vaaBytes, VAAStatusConfirmed)
+	if err := f.relayer.RequeueVAA(rec.Key); err == nil {
+		t.Error("requeueing a confirmed VAA succeeded")
+	}
+}
+
+func TestRequeueDeadLettered(t *testing.T) {
+	store := NewMemoryVAAStore()
+	if err := RequeueDeadLettered(store, "missing"); !errors.Is(err, ErrVAANotFound) {
+		t.Errorf("requeueing a missing VAA: %v, want ErrVAANotFound", err)
+	}
+
+	store.Update("dead", func(rec *VAARecord) error {
+		rec.Status, rec.Attempts, rec.NextAttemptAt = VAAStatusDeadLettered, 8, time.Now().Add(time.Hour)
+		return nil
+	})
+	if err := RequeueDeadLettered(store, "dead"); err != nil {
+		t.Fatalf("RequeueDeadLettered: %v", err)
+	}
+	rec, _ := store.Get("dead")
+	if rec.Status != VAAStatusFailed || rec.Attempts != 0 || !rec.NextAttemptAt.IsZero() {
+		t.Errorf("requeued record is %s with %d attempts due at %s, want failed, 0 and now", rec.Status, rec.Attempts, rec.NextAttemptAt)
+	}
+}
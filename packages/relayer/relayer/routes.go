@@ -0,0 +1,329 @@
+package relayer
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/wormhole-foundation/wormhole/aztec/relayer/payload"
+	vaaLib "github.com/wormhole-foundation/wormhole/sdk/vaa"
+)
+
+// Client types a route can deliver with
+const (
+	RouteClientEVM   = "evm"   // Treasury verify() on an EVM chain
+	RouteClientAztec = "aztec" // Verification service, falling back to the PXE
+)
+
+// Legacy route names, used when ROUTES is not set
+const (
+	routeAztecToArbitrum = "aztec-to-arbitrum"
+	routeArbitrumToAztec = "arbitrum-to-aztec"
+)
+
+// routeUnmatched labels VAAs that match no route in logs and metrics
+const routeUnmatched = "unmatched"
+
+// routeNamePattern keeps route names usable in URLs and metric labels
+var routeNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// Route relays VAAs from allowlisted emitters on one chain to a target contract on another
+type Route struct {
+	Name           string           // Unique name, used in logs, metric labels and the admin API
+	SourceChainID  uint16           // Wormhole chain ID the VAAs are emitted on
+	Emitters       EmitterAllowlist // Emitters whose VAAs this route delivers
+	DestChainID    uint16           // Wormhole chain ID the VAAs are delivered to
+	TargetContract string           // Contract that receives the VAA
+	Client         string           // RouteClientEVM or RouteClientAztec
+	RPCURL         string           // JSON-RPC endpoint of the destination chain, for EVM routes
+	NetworkID      uint64           // EVM network ID the RPC must report (0 uses the known ID of DestChainID)
+	Ordered        bool             // Deliver each emitter's VAAs strictly in sequence order
+	// First sequence to deliver per emitter on an ordered route, for emitters with no finished
+	// delivery in the store. Emitters not listed start at 0.
+	StartSequences map[string]uint64
+}
+
+// routeJSON is the format of a route in the ROUTES environment variable and the config file
+type routeJSON struct {
+	Name           string   `json:"name" yaml:"name"`
+	SourceChain    uint16   `json:"sourceChain" yaml:"sourceChain"`
+	Emitters       []string `json:"emitters" yaml:"emitters"`
+	DestChain      uint16   `json:"destChain" yaml:"destChain"`
+	TargetContract string   `json:"targetContract" yaml:"targetContract"`
+	Client         string   `json:"client" yaml:"client"`
+	RPCURL         string   `json:"rpcUrl" yaml:"rpcUrl"`
+	NetworkID      uint64   `json:"networkId" yaml:"networkId"`
+	Ordered        bool     `json:"ordered" yaml:"ordered"`
+
+	StartSequences map[string]uint64 `json:"startSequences" yaml:"startSequences"`
+}
+
+// buildRoutes converts route entries to Routes, normalizing their emitters
+func buildRoutes(entries []routeJSON) ([]Route, error) {
+	routes := make([]Route, 0, len(entries))
+	for _, entry := range entries {
+		emitters := make(EmitterAllowlist)
+		for _, address := range entry.Emitters {
+			emitter, err := normalizeEmitter(address)
+			if err != nil {
+				return nil, fmt.Errorf("route %q: %v", entry.Name, err)
+			}
+			emitters[emitter] = struct{}{}
+		}
+		var startSequences map[string]uint64
+		for address, sequence := range entry.StartSequences {
+			emitter, err := normalizeEmitter(address)
+			if err != nil {
+				return nil, fmt.Errorf("route %q: start sequence: %v", entry.Name, err)
+			}
+			if startSequences == nil {
+				startSequences = make(map[string]uint64)
+			}
+			startSequences[emitter] = sequence
+		}
+		routes = append(routes, Route{
+			Name:           entry.Name,
+			SourceChainID:  entry.SourceChain,
+			Emitters:       emitters,
+			DestChainID:    entry.DestChain,
+			TargetContract: entry.TargetContract,
+			Client:         entry.Client,
+			RPCURL:         entry.RPCURL,
+			NetworkID:      entry.NetworkID,
+			Ordered:        entry.Ordered,
+			StartSequences: startSequences,
+		})
+	}
+	return routes, nil
+}
+
+// validateRoutes checks that routes are complete and that every VAA matches at most one of them
+func validateRoutes(routes []Route) error {
+	if len(routes) == 0 {
+		return fmt.Errorf("no routes configured")
+	}
+
+	names := make(map[string]bool)
+	rpcByChain := make(map[uint16]string)
+	networkByChain := make(map[uint16]uint64)
+	owners := make(map[string][]Route) // source chain and emitter -> routes delivering it
+	for _, route := range routes {
+		if !routeNamePattern.MatchString(route.Name) {
+			return fmt.Errorf("route name %q must be lowercase letters, digits, '-' or '_'", route.Name)
+		}
+		if names[route.Name] {
+			return fmt.Errorf("duplicate route name %q", route.Name)
+		}
+		names[route.Name] = true
+
+		if route.SourceChainID == 0 || route.DestChainID == 0 {
+			return fmt.Errorf("route %q: source and destination chains are required", route.Name)
+		}
+		if route.TargetContract == "" {
+			return fmt.Errorf("route %q: target contract is required", route.Name)
+		}
+
+		switch route.Client {
+		case RouteClientEVM:
+			if !common.IsHexAddress(route.TargetContract) {
+				return fmt.Errorf("route %q: invalid target contract %q", route.Name, route.TargetContract)
+			}
+			if route.RPCURL == "" {
+				return fmt.Errorf("route %q: rpcUrl is required for evm routes", route.Name)
+			}
+			// One evm.Client, and so one nonce sequence, serves each destination chain
+			if rpc, ok := rpcByChain[route.DestChainID]; ok && rpc != route.RPCURL {
+				return fmt.Errorf("route %q: chain %d already uses RPC %s", route.Name, route.DestChainID, rpc)
+			}
+			rpcByChain[route.DestChainID] = route.RPCURL
+
+			networkID := route.networkID()
+			if networkID == 0 {
+				return fmt.Errorf("route %q: networkId is required for chain %d", route.Name, route.DestChainID)
+			}
+			if id, ok := networkByChain[route.DestChainID]; ok && id != networkID {
+				return fmt.Errorf("route %q: chain %d already uses network ID %d", route.Name, route.DestChainID, id)
+			}
+			networkByChain[route.DestChainID] = networkID
+		case RouteClientAztec:
+			if !isAztecAddress(route.TargetContract) {
+				return fmt.Errorf("route %q: invalid Aztec target contract %q", route.Name, route.TargetContract)
+			}
+		default:
+			return fmt.Errorf("route %q: unknown client %q", route.Name, route.Client)
+		}
+
+		for emitter := range route.StartSequences {
+			if !route.Ordered {
+				return fmt.Errorf("route %q: start sequences are only used by ordered routes", route.Name)
+			}
+			if !route.Emitters.Allows(emitter) {
+				return fmt.Errorf("route %q: start sequence for emitter %s, which the route does not allowlist", route.Name, emitter)
+			}
+		}
+
+		// EVM routes may share an emitter when they pay out on different chains, since the
+		// payload's target chain picks between them
+		for emitter := range route.Emitters {
+			id := fmt.Sprintf("%d/%s", route.SourceChainID, emitter)
+			for _, owner := range owners[id] {
+				if route.Client != RouteClientEVM || owner.Client != RouteClientEVM || owner.DestChainID == route.DestChainID {
+					return fmt.Errorf("emitter %s on chain %d is used by routes %q and %q", emitter, route.SourceChainID, owner.Name, route.Name)
+				}
+				if owner.Ordered != route.Ordered {
+					return fmt.Errorf("routes %q and %q share emitter %s on chain %d but only one is ordered", owner.Name, route.Name, emitter, route.SourceChainID)
+				}
+				if owner.StartSequences[emitter] != route.StartSequences[emitter] {
+					return fmt.Errorf("routes %q and %q share emitter %s on chain %d but start it at different sequences", owner.Name, route.Name, emitter, route.SourceChainID)
+				}
+			}
+			owners[id] = append(owners[id], route)
+		}
+	}
+	return nil
+}
+
+// legacyRoutes builds the Aztec<->Arbitrum route pair from the single-pair settings
+func legacyRoutes(config Config) []Route {
+	return []Route{
+		{
+			Name:           routeAztecToArbitrum,
+			SourceChainID:  config.SourceChainID,
+			Emitters:       config.AztecEmitters,
+			DestChainID:    config.DestChainID,
+			TargetContract: config.ArbitrumTargetContract,
+			Client:         RouteClientEVM,
+			RPCURL:         config.ArbitrumRPCURL,
+			NetworkID:      config.ArbitrumNetworkID,
+		},
+		{
+			Name:           routeArbitrumToAztec,
+			SourceChainID:  config.DestChainID,
+			Emitters:       config.ArbitrumEmitters,
+			DestChainID:    config.SourceChainID,
+			TargetContract: config.AztecTargetContract,
+			Client:         RouteClientAztec,
+		},
+	}
+}
+
+// evmNetworkIDs maps Wormhole chain IDs to the EVM network IDs their RPCs report
+var evmNetworkIDs = map[uint16]uint64{
+	2:     1,        // Ethereum
+	23:    42161,    // Arbitrum
+	24:    10,       // Optimism
+	30:    8453,     // Base
+	10002: 11155111, // Sepolia
+	10003: 421614,   // Arbitrum Sepolia
+	10004: 84532,    // Base Sepolia
+	10005: 11155420, // Optimism Sepolia
+}
+
+// networkID returns the EVM network ID the route's RPC must report, or 0 if it is unknown
+func (r Route) networkID() uint64 {
+	if r.NetworkID != 0 {
+		return r.NetworkID
+	}
+	return evmNetworkIDs[r.DestChainID]
+}
+
+// RouteTable returns the configured routes, or the legacy pair if none are configured
+func (c Config) RouteTable() []Route {
+	if len(c.Routes) > 0 {
+		return c.Routes
+	}
+	return legacyRoutes(c)
+}
+
+// currentRoutes returns the route table, whose emitter allowlists can change at runtime
+func (r *Relayer) currentRoutes() []Route {
+	r.routesMu.RLock()
+	defer r.routesMu.RUnlock()
+	return r.routes
+}
+
+// routeByName returns the route called name
+func (r *Relayer) routeByName(name string) (Route, bool) {
+	for _, route := range r.currentRoutes() {
+		if route.Name == name {
+			return route, true
+		}
+	}
+	return Route{}, false
+}
+
+// matchRoute returns the route that delivers a VAA from emitterHex on chainID. When several
+// EVM routes share the emitter, the target chain in vaaPayload picks one of them, and a zero
+// target chain picks the first. If no route matches, it returns the reason: "chain" when no
+// route starts on chainID, "emitter" when the emitter is not allowlisted and "target_chain"
+// when no route pays out on the target chain.
+func (r *Relayer) matchRoute(chainID uint16, emitterHex string, vaaPayload []byte) (Route, string) {
+	reason := "chain"
+	var candidates []Route
+	for _, route := range r.currentRoutes() {
+		if route.SourceChainID != chainID {
+			continue
+		}
+		if route.Emitters.Allows(emitterHex) {
+			candidates = append(candidates, route)
+			continue
+		}
+		reason = "emitter"
+	}
+	if len(candidates) == 0 {
+		return Route{}, reason
+	}
+	if candidates[0].Client != RouteClientEVM {
+		return candidates[0], ""
+	}
+
+	// A malformed payload goes to the default route, which rejects it
+	transfer, err := payload.Decode(vaaPayload)
+	if err != nil || transfer.TargetChain == 0 {
+		return candidates[0], ""
+	}
+	for _, route := range candidates {
+		if route.DestChainID == transfer.TargetChain {
+			return route, ""
+		}
+	}
+	return Route{}, "target_chain"
+}
+
+// matchRecordRoute returns the route of a stored VAA, as matchRoute does
+func (r *Relayer) matchRecordRoute(rec *VAARecord) (Route, string) {
+	v, err := vaaLib.Unmarshal(rec.RawBytes)
+	if err != nil {
+		return Route{}, "payload"
+	}
+	return r.matchRoute(rec.ChainID, rec.EmitterHex, v.Payload)
+}
+
+// routeNameOf returns the name of the route for a stored VAA, or routeUnmatched
+func (r *Relayer) routeNameOf(rec *VAARecord) string {
+	route, reason := r.matchRecordRoute(rec)
+	if reason != "" {
+		return routeUnmatched
+	}
+	return route.Name
+}
+
+// routeNames returns the names of all routes in sorted order
+func (r *Relayer) routeNames() []string {
+	var names []string
+	for _, route := range r.currentRoutes() {
+		names = append(names, route.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// evmClientFor returns the EVM client delivering on route
+func (r *Relayer) evmClientFor(route Route) (EVMSubmitter, error) {
+	client, ok := r.evmClients[route.DestChainID]
+	if !ok {
+		return nil, fmt.Errorf("no EVM client for chain %d", route.DestChainID)
+	}
+	return client, nil
+}
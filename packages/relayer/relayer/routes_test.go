@@ -0,0 +1,218 @@
+package relayer
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/wormhole-foundation/wormhole/aztec/relayer/payload"
+	"github.com/wormhole-foundation/wormhole/aztec/relayer/relayertest"
+	vaaLib "github.com/wormhole-foundation/wormhole/sdk/vaa"
+)
+
+const e2eBaseChain = 10004 // Base Sepolia, a second EVM destination
+
+// testEVMRoute returns a valid route delivering the Aztec emitter's VAAs to destChain
+func testEVMRoute(name string, destChain uint16) Route {
+	return Route{
+		Name:           name,
+		SourceChainID:  e2eAztecChain,
+		Emitters:       EmitterAllowlist{e2eAztecEmitter.String(): {}},
+		DestChainID:    destChain,
+		TargetContract: relayertest.TreasuryAddress.Hex(),
+		Client:         RouteClientEVM,
+		RPCURL:         "http://rpc-" + name,
+	}
+}
+
+// testAztecRoute returns a valid route delivering the EVM emitter's VAAs to Aztec
+func testAztecRoute(name string) Route {
+	return Route{
+		Name:           name,
+		SourceChainID:  e2eEVMChain,
+		Emitters:       EmitterAllowlist{e2eEVMEmitter.String(): {}},
+		DestChainID:    e2eAztecChain,
+		TargetContract: e2eAztecTarget,
+		Client:         RouteClientAztec,
+	}
+}
+
+func TestValidateRoutes(t *testing.T) {
+	cases := []struct {
+		name    string
+		routes  func() []Route
+		wantErr string // Empty when the routes are valid
+	}{
+		{
+			name:   "one route each way",
+			routes: func() []Route { return []Route{testEVMRoute("to-evm", e2eEVMChain), testAztecRoute("to-aztec")} },
+		},
+		{
+			name:    "no routes",
+			routes:  func() []Route { return nil },
+			wantErr: "no routes configured",
+		},
+		{
+			name:    "duplicate name",
+			routes:  func() []Route { return []Route{testEVMRoute("same", e2eEVMChain), testAztecRoute("same")} },
+			wantErr: `duplicate route name "same"`,
+		},
+		{
+			name:    "name unusable in URLs",
+			routes:  func() []Route { return []Route{testEVMRoute("To EVM", e2eEVMChain)} },
+			wantErr: "must be lowercase letters",
+		},
+		{
+			name: "unknown client",
+			routes: func() []Route {
+				route := testEVMRoute("to-solana", 1)
+				route.Client = "solana"
+				return []Route{route}
+			},
+			wantErr: `unknown client "solana"`,
+		},
+		{
+			name: "invalid Aztec target contract",
+			routes: func() []Route {
+				route := testAztecRoute("to-aztec")
+				route.TargetContract = relayertest.TreasuryAddress.Hex()
+				return []Route{route}
+			},
+			wantErr: "invalid Aztec target contract",
+		},
+		{
+			name:    "unknown network ID",
+			routes:  func() []Route { return []Route{testEVMRoute("to-unknown", 9999)} },
+			wantErr: "networkId is required",
+		},
+		{
+			name: "two RPCs for one chain",
+			routes: func() []Route {
+				return []Route{testEVMRoute("to-evm", e2eEVMChain), testEVMRoute("to-evm-again", e2eEVMChain)}
+			},
+			wantErr: "already uses RPC",
+		},
+		{
+			name: "overlapping EVM routes to one chain",
+			routes: func() []Route {
+				second := testEVMRoute("to-evm-again", e2eEVMChain)
+				second.RPCURL = "http://rpc-to-evm"
+				return []Route{testEVMRoute("to-evm", e2eEVMChain), second}
+			},
+			wantErr: `used by routes "to-evm" and "to-evm-again"`,
+		},
+		{
+			name: "emitter shared with an Aztec route",
+			routes: func() []Route {
+				aztec := testAztecRoute("to-aztec")
+				aztec.SourceChainID = e2eAztecChain
+				aztec.Emitters = EmitterAllowlist{e2eAztecEmitter.String(): {}}
+				return []Route{testEVMRoute("to-evm", e2eEVMChain), aztec}
+			},
+			wantErr: `used by routes "to-evm" and "to-aztec"`,
+		},
+		{
+			name: "EVM routes sharing an emitter across target chains",
+			routes: func() []Route {
+				return []Route{testEVMRoute("to-evm", e2eEVMChain), testEVMRoute("to-base", e2eBaseChain)}
+			},
+		},
+		{
+			name: "shared emitter ordered on one route only",
+			routes: func() []Route {
+				base := testEVMRoute("to-base", e2eBaseChain)
+				base.Ordered = true
+				return []Route{testEVMRoute("to-evm", e2eEVMChain), base}
+			},
+			wantErr: "only one is ordered",
+		},
+		{
+			name: "start sequence on an unordered route",
+			routes: func() []Route {
+				route := testEVMRoute("to-evm", e2eEVMChain)
+				route.StartSequences = map[string]uint64{e2eAztecEmitter.String(): 5}
+				return []Route{route}
+			},
+			wantErr: "only used by ordered routes",
+		},
+		{
+			name: "start sequence for an emitter not on the route",
+			routes: func() []Route {
+				route := testEVMRoute("to-evm", e2eEVMChain)
+				route.Ordered = true
+				route.StartSequences = map[string]uint64{e2eEVMEmitter.String(): 5}
+				return []Route{route}
+			},
+			wantErr: "does not allowlist",
+		},
+		{
+			name: "shared emitter started at different sequences",
+			routes: func() []Route {
+				evm, base := testEVMRoute("to-evm", e2eEVMChain), testEVMRoute("to-base", e2eBaseChain)
+				evm.Ordered, base.Ordered = true, true
+				evm.StartSequences = map[string]uint64{e2eAztecEmitter.String(): 5}
+				return []Route{evm, base}
+			},
+			wantErr: "start it at different sequences",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateRoutes(tc.routes())
+			switch {
+			case tc.wantErr == "" && err != nil:
+				t.Errorf("validateRoutes: %v", err)
+			case tc.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tc.wantErr)):
+				t.Errorf("validateRoutes = %v, want an error containing %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestMatchRoute(t *testing.T) {
+	r := &Relayer{routes: []Route{
+		testEVMRoute("to-evm", e2eEVMChain),
+		testEVMRoute("to-base", e2eBaseChain),
+		testAztecRoute("to-aztec"),
+	}}
+	transferTo := func(targetChain uint16) []byte {
+		transfer := &payload.TransferPayload{
+			Token:       common.HexToAddress("0x75faf114eafb1BDbe2F0316DF893fd58CE46AA4d"),
+			Recipient:   common.HexToAddress("0x1bF9Dbd1D52fb0AB6a8D1f1B2A7D2b8a3cE8c1F0"),
+			Amount:      big.NewInt(1000),
+			TargetChain: targetChain,
+		}
+		encoded, err := transfer.Encode()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return encoded
+	}
+
+	cases := []struct {
+		name       string
+		chainID    uint16
+		emitter    vaaLib.Address
+		payload    []byte
+		wantRoute  string
+		wantReason string
+	}{
+		{"target chain picks the route", e2eAztecChain, e2eAztecEmitter, transferTo(e2eBaseChain), "to-base", ""},
+		{"target chain of the first route", e2eAztecChain, e2eAztecEmitter, transferTo(e2eEVMChain), "to-evm", ""},
+		{"zero target chain takes the first route", e2eAztecChain, e2eAztecEmitter, transferTo(0), "to-evm", ""},
+		{"malformed payload takes the first route", e2eAztecChain, e2eAztecEmitter, []byte{1, 2, 3}, "to-evm", ""},
+		{"target chain without a route", e2eAztecChain, e2eAztecEmitter, transferTo(24), "", "target_chain"},
+		{"Aztec route ignores the payload", e2eEVMChain, e2eEVMEmitter, transferTo(e2eBaseChain), "to-aztec", ""},
+		{"emitter not allowlisted", e2eAztecChain, vaaLib.Address{31: 0xee}, transferTo(e2eEVMChain), "", "emitter"},
+		{"no route from the chain", 2, e2eAztecEmitter, transferTo(e2eEVMChain), "", "chain"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			route, reason := r.matchRoute(tc.chainID, tc.emitter.String(), tc.payload)
+			if route.Name != tc.wantRoute || reason != tc.wantReason {
+				t.Errorf("matchRoute = %q, %q; want %q, %q", route.Name, reason, tc.wantRoute, tc.wantReason)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package relayer
+
+import "fmt"
+
+// redacted replaces secret values whenever they are formatted, logged or marshalled
+const redacted = "[REDACTED]"
+
+// Secret is a string that never prints its value. Use Reveal where the value is needed.
+type Secret string
+
+// Reveal returns the secret value
+func (s Secret) Reveal() string {
+	return string(s)
+}
+
+// String redacts the secret for %s and %v
+func (s Secret) String() string {
+	if s == "" {
+		return ""
+	}
+	return redacted
+}
+
+// GoString redacts the secret for %#v
+func (s Secret) GoString() string {
+	return fmt.Sprintf("%q", s.String())
+}
+
+// MarshalText redacts the secret in JSON and other text encodings
+func (s Secret) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// String formats the Config with its secrets redacted
+func (c Config) String() string {
+	type plainConfig Config // Drops this method so formatting does not recurse
+	return fmt.Sprintf("%+v", plainConfig(c))
+}
@@ -0,0 +1,55 @@
+package relayer
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestSecretRedaction(t *testing.T) {
+	const value = "0xdeadbeefcafe"
+	secret := Secret(value)
+
+	if got := secret.Reveal(); got != value {
+		t.Errorf("Reveal() = %q, want the value", got)
+	}
+	encoded, err := json.Marshal(struct{ Key Secret }{secret})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, got := range map[string]string{
+		"String":      secret.String(),
+		"%v":          fmt.Sprintf("%v", secret),
+		"%s":          fmt.Sprintf("%s", secret),
+		"%#v":         fmt.Sprintf("%#v", secret),
+		"%+v":         fmt.Sprintf("%+v", struct{ Key Secret }{secret}),
+		"MarshalText": string(encoded),
+	} {
+		if strings.Contains(got, value) || !strings.Contains(got, redacted) {
+			t.Errorf("%s = %s, want the value redacted", name, got)
+		}
+	}
+
+	// An unset secret stays visibly unset
+	if got := Secret("").String(); got != "" {
+		t.Errorf("empty secret formats as %q, want empty", got)
+	}
+}
+
+func TestConfigStringRedactsSecrets(t *testing.T) {
+	config := Config{
+		SpyRPCHost: "spy:7073",
+		Signer:     SignerConfig{Type: SignerKey, PrivateKey: Secret("0xprivatekey")},
+		AdminToken: Secret("admin-token"),
+	}
+
+	for _, got := range []string{config.String(), fmt.Sprintf("%v", config), fmt.Sprintf("%#v", config)} {
+		if strings.Contains(got, "0xprivatekey") || strings.Contains(got, "admin-token") {
+			t.Errorf("formatted config leaks a secret: %s", got)
+		}
+	}
+	if got := config.String(); !strings.Contains(got, "spy:7073") {
+		t.Errorf("Config.String() = %s, want the other settings kept", got)
+	}
+}
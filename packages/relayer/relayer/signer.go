@@ -0,0 +1,120 @@
+package relayer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/wormhole-foundation/wormhole/aztec/relayer/evm"
+	"go.uber.org/zap"
+)
+
+// Signer types selectable with SignerConfig.Type
+const (
+	SignerKey      = "key"      // Hex private key held in memory
+	SignerKeystore = "keystore" // Encrypted geth keystore file, decrypted at startup
+	SignerRemote   = "remote"   // eth_signTransaction over JSON-RPC (Clef, web3signer)
+	SignerPKCS11   = "pkcs11"   // Key held in a PKCS#11 HSM; needs the pkcs11 build tag
+)
+
+// SignerConfig selects and configures the EVM transaction signer
+type SignerConfig struct {
+	Type                 string // SignerKey, SignerKeystore, SignerRemote or SignerPKCS11 ("" infers key or keystore)
+	PrivateKey           Secret // Hex private key for SignerKey
+	KeystoreFile         string // Encrypted geth keystore file for SignerKeystore
+	KeystorePasswordFile string // File holding the keystore password
+	RemoteURL            string // JSON-RPC endpoint of the remote signer
+	RemoteAddress        string // Account the remote signer signs for ("" uses its first account)
+	PKCS11Module         string // Path to the PKCS#11 library, e.g. libsofthsm2.so
+	PKCS11TokenLabel     string // Label of the token holding the key
+	PKCS11KeyLabel       string // Label of the secp256k1 key pair on the token
+	PKCS11PINFile        string // File holding the token user PIN
+}
+
+// signerType returns the configured signer type, inferring key or keystore when unset
+func (c SignerConfig) signerType() string {
+	if c.Type != "" {
+		return c.Type
+	}
+	if c.KeystoreFile != "" {
+		return SignerKeystore
+	}
+	return SignerKey
+}
+
+// validate checks the settings of the selected signer without contacting or decrypting anything
+func (c SignerConfig) validate() error {
+	if c.Type == "" && c.PrivateKey != "" && c.KeystoreFile != "" {
+		return fmt.Errorf("set either a private key or a keystore file, not both")
+	}
+
+	switch c.signerType() {
+	case SignerKey:
+		if c.PrivateKey == "" {
+			return fmt.Errorf("no EVM signing key configured: set PRIVATE_KEY, EVM_KEYSTORE_FILE or EVM_SIGNER")
+		}
+		if _, err := crypto.HexToECDSA(strings.TrimPrefix(c.PrivateKey.Reveal(), "0x")); err != nil {
+			return fmt.Errorf("private key is not a valid hex secp256k1 key")
+		}
+	case SignerKeystore:
+		if c.KeystoreFile == "" || c.KeystorePasswordFile == "" {
+			return fmt.Errorf("keystore signer needs a keystore file and a password file")
+		}
+		return checkFilesExist(c.KeystoreFile, c.KeystorePasswordFile)
+	case SignerRemote:
+		if c.RemoteURL == "" {
+			return fmt.Errorf("remote signer needs a URL")
+		}
+		if c.RemoteAddress != "" && !common.IsHexAddress(c.RemoteAddress) {
+			return fmt.Errorf("invalid remote signer address %q", c.RemoteAddress)
+		}
+	case SignerPKCS11:
+		if c.PKCS11Module == "" || c.PKCS11TokenLabel == "" || c.PKCS11KeyLabel == "" || c.PKCS11PINFile == "" {
+			return fmt.Errorf("pkcs11 signer needs a module, token label, key label and PIN file")
+		}
+		return checkFilesExist(c.PKCS11Module, c.PKCS11PINFile)
+	default:
+		return fmt.Errorf("unknown signer type %q", c.Type)
+	}
+	return nil
+}
+
+// closeSigner releases what a signer holds open, such as a PKCS#11 session or a connection to
+// a remote signer
+func closeSigner(signer evm.Signer) {
+	if closer, ok := signer.(interface{ Close() }); ok {
+		closer.Close()
+	}
+}
+
+func checkFilesExist(paths ...string) error {
+	for _, path := range paths {
+		if _, err := os.Stat(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NewSigner creates the signer selected by config
+func NewSigner(ctx context.Context, config SignerConfig, logger *zap.Logger) (evm.Signer, error) {
+	switch config.signerType() {
+	case SignerKey:
+		privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(config.PrivateKey.Reveal(), "0x"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid private key")
+		}
+		return evm.NewKeySigner(privateKey), nil
+	case SignerKeystore:
+		return evm.NewKeystoreSigner(config.KeystoreFile, config.KeystorePasswordFile)
+	case SignerRemote:
+		return evm.NewRemoteSigner(ctx, config.RemoteURL, config.RemoteAddress, logger)
+	case SignerPKCS11:
+		return evm.NewPKCS11Signer(config.PKCS11Module, config.PKCS11TokenLabel, config.PKCS11KeyLabel, config.PKCS11PINFile)
+	default:
+		return nil, fmt.Errorf("unknown signer type %q", config.Type)
+	}
+}
@@ -0,0 +1,93 @@
+package relayer
+
+import (
+	"fmt"
+
+	publicrpcv1 "github.com/certusone/wormhole/node/pkg/proto/publicrpc/v1"
+	spyv1 "github.com/certusone/wormhole/node/pkg/proto/spy/v1"
+	"go.uber.org/zap"
+)
+
+// buildSpyFilters turns the route emitter allowlists into spy emitter filters, so the spy
+// only streams VAAs the relayer would deliver. Routes sharing an emitter share its filter.
+func buildSpyFilters(routes []Route) []*spyv1.FilterEntry {
+	type source struct {
+		chainID uint16
+		emitter string
+	}
+	seen := make(map[source]bool)
+	var filters []*spyv1.FilterEntry
+	for _, route := range routes {
+		for _, emitter := range route.Emitters.List() {
+			if seen[source{route.SourceChainID, emitter}] {
+				continue
+			}
+			seen[source{route.SourceChainID, emitter}] = true
+			filters = append(filters, &spyv1.FilterEntry{
+				Filter: &spyv1.FilterEntry_EmitterFilter{
+					EmitterFilter: &spyv1.EmitterFilter{
+						ChainId:        publicrpcv1.ChainID(route.SourceChainID),
+						EmitterAddress: emitter,
+					},
+				},
+			})
+		}
+	}
+	return filters
+}
+
+// UpdateRouteEmitters replaces the emitter allowlists of existing routes, matched by name,
+// and resubscribes to the spy with matching filters. Adding or removing routes needs a restart.
+// If the updated allowlists would make the route table invalid, nothing changes.
+func (r *Relayer) UpdateRouteEmitters(updated []Route) error {
+	byName := make(map[string]Route, len(updated))
+	for _, route := range updated {
+		byName[route.Name] = route
+	}
+
+	r.routesMu.Lock()
+	routes := make([]Route, len(r.routes))
+	var changed []Route
+	var missing []string
+	for i, route := range r.routes {
+		if next, ok := byName[route.Name]; ok {
+			route.Emitters = next.Emitters
+			route.StartSequences = next.StartSequences
+			delete(byName, route.Name)
+			changed = append(changed, route)
+		} else {
+			missing = append(missing, route.Name)
+		}
+		routes[i] = route
+	}
+	if err := validateRoutes(routes); err != nil {
+		r.routesMu.Unlock()
+		return fmt.Errorf("invalid route emitters: %v", err)
+	}
+	r.routes = routes
+	r.routesMu.Unlock()
+
+	for _, route := range changed {
+		r.logger.Info("Route emitters updated",
+			zap.String("route", route.Name),
+			zap.Strings("emitters", route.Emitters.List()))
+	}
+	for _, name := range missing {
+		r.logger.Warn("Route missing from reloaded configuration, keeping its emitters", zap.String("route", name))
+	}
+	for name := range byName {
+		r.logger.Warn("Ignoring new route until restart", zap.String("route", name))
+	}
+	r.applySpyFilters()
+	return nil
+}
+
+// applySpyFilters pushes filters for the current route allowlists to the spy client
+func (r *Relayer) applySpyFilters() {
+	filters := buildSpyFilters(r.currentRoutes())
+	if len(filters) == 0 {
+		// An empty filter list means "everything" to the spy; route matching still drops it all
+		r.logger.Warn("No emitters allowlisted, subscribing to the unfiltered spy stream")
+	}
+	r.spyClient.SetFilters(filters)
+}
@@ -0,0 +1,90 @@
+package relayer
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/wormhole-foundation/wormhole/aztec/relayer/payload"
+	vaaLib "github.com/wormhole-foundation/wormhole/sdk/vaa"
+)
+
+func TestBuildSpyFiltersDedupesSharedEmitters(t *testing.T) {
+	shared, _ := ParseEmitterAllowlist(e2eAztecEmitter.String())
+	both, _ := ParseEmitterAllowlist(e2eAztecEmitter.String() + "," + e2eEVMEmitter.String())
+	filters := buildSpyFilters([]Route{
+		{Name: "to-arbitrum", SourceChainID: e2eAztecChain, Emitters: shared},
+		{Name: "to-base", SourceChainID: e2eAztecChain, Emitters: both},
+		{Name: "from-evm", SourceChainID: e2eEVMChain, Emitters: shared},
+	})
+
+	// The shared emitter is filtered once per source chain
+	if len(filters) != 3 {
+		t.Fatalf("built %d filters, want 3: %v", len(filters), filters)
+	}
+}
+
+func TestUpdateRouteEmitters(t *testing.T) {
+	otherEmitter := vaaLib.Address{31: 0xa2}
+	f := startFakeRun(t, func(fc *fileConfig) {
+		fc.Routes = append(fc.Routes, routeJSON{
+			Name:           "aztec-loopback",
+			SourceChain:    e2eAztecChain,
+			Emitters:       []string{otherEmitter.String()},
+			DestChain:      e2eAztecChain,
+			TargetContract: e2eAztecTarget,
+			Client:         RouteClientAztec,
+		})
+	})
+	routes := f.relayer.currentRoutes()
+
+	// Allowlisting the Aztec -> EVM route's emitter on the loopback route makes its VAAs ambiguous
+	conflicting := make([]Route, len(routes))
+	copy(conflicting, routes)
+	conflicting[2].Emitters, _ = ParseEmitterAllowlist(e2eAztecEmitter.String())
+	if err := f.relayer.UpdateRouteEmitters(conflicting); err == nil {
+		t.Fatal("UpdateRouteEmitters accepted an emitter shared by an EVM and an Aztec route")
+	}
+	if route, _ := f.relayer.routeByName("aztec-loopback"); !route.Emitters.Allows(otherEmitter.String()) {
+		t.Errorf("rejected update changed the emitters to %v", route.Emitters.List())
+	}
+
+	updated := make([]Route, len(routes))
+	copy(updated, routes)
+	updated[2].Emitters, _ = ParseEmitterAllowlist(otherEmitter.String() + ",0xa3")
+	if err := f.relayer.UpdateRouteEmitters(updated); err != nil {
+		t.Fatalf("UpdateRouteEmitters: %v", err)
+	}
+	if route, _ := f.relayer.routeByName("aztec-loopback"); !route.Emitters.Allows(vaaLib.Address{31: 0xa3}.String()) {
+		t.Errorf("emitters = %v after the update, want 0xa3 added", route.Emitters.List())
+	}
+	if n := len(f.source.Filters()); n != 4 {
+		t.Errorf("spy has %d filters after the update, want 4", n)
+	}
+}
+
+func TestNonAllowlistedEmitterIsDroppedUnrecorded(t *testing.T) {
+	f := startFakeRun(t, nil)
+
+	// The spy filters would hide it, so hand it to the relayer directly as an unfiltered spy would
+	v := f.guardians.NewVAA(e2eEVMChain, vaaLib.Address{31: 0xee}, f.nextSequence(), make([]byte, payload.Length))
+	vaaBytes := f.guardians.Sign(t, v)
+	key := computeVAAKey(vaaBytes)
+	if !f.relayer.beginProcessingVAA(key) {
+		t.Fatal("VAA was already claimed")
+	}
+	f.relayer.dispatchVAA(context.Background(), vaaBytes, key)
+
+	waitFor(t, "the VAA to be processed", func() bool {
+		f.relayer.dedupeMu.Lock()
+		defer f.relayer.dedupeMu.Unlock()
+		_, inFlight := f.relayer.inflightVAAs[key]
+		return !inFlight
+	})
+	if rec, err := f.relayer.store.Get(key); !errors.Is(err, ErrVAANotFound) {
+		t.Errorf("non-allowlisted VAA was recorded as %v (%v), want no record", rec, err)
+	}
+	if sent := len(f.aztec.Sent()) + len(f.verifier.Verified()); sent != 0 {
+		t.Errorf("non-allowlisted VAA was delivered %d times", sent)
+	}
+}
@@ -0,0 +1,458 @@
+package relayer
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// VAAStatus is the delivery lifecycle state of a VAA
+type VAAStatus string
+
+const (
+	VAAStatusReceived     VAAStatus = "received"      // Seen on the spy stream, nothing sent yet
+	VAAStatusSubmitted    VAAStatus = "submitted"     // Delivery transaction sent to the destination chain
+	VAAStatusConfirmed    VAAStatus = "confirmed"     // Delivery completed successfully
+	VAAStatusFailed       VAAStatus = "failed"        // Delivery attempt failed, waiting for a retry
+	VAAStatusDeadLettered VAAStatus = "dead_lettered" // Retries exhausted, waiting for an operator
+)
+
+// ErrVAANotFound is returned when a VAA key is not present in the store
+var ErrVAANotFound = errors.New("vaa not found")
+
+// VAARecord is the persisted delivery state of a single VAA
+type VAARecord struct {
+	Key               string    `json:"key"`               // computeVAAKey of the raw bytes
+	RawBytes          []byte    `json:"rawBytes"`          // Raw VAA bytes, needed to resume delivery
+	ChainID           uint16    `json:"chainId"`           // Emitter chain ID
+	EmitterHex        string    `json:"emitter"`           // Hex-encoded emitter address
+	Sequence          uint64    `json:"sequence"`          // VAA sequence number
+	Status            VAAStatus `json:"status"`            // Current lifecycle state
+	TxHashes          []string  `json:"txHashes"`          // Delivery transactions, oldest first
+	PendingTxHash     string    `json:"pendingTxHash"`     // Latest EVM delivery transaction not yet seen mined
+	LastError         string    `json:"lastError"`         // Error from the most recent failed attempt
+	SignatureRejected bool      `json:"signatureRejected"` // Dead-lettered for failing guardian verification
+	Attempts          int       `json:"attempts"`          // Failed delivery attempts so far
+	NextAttemptAt     time.Time `json:"nextAttemptAt"`     // Earliest time the retry scheduler may try again
+	CreatedAt         time.Time `json:"createdAt"`         // When the VAA was first received
+	UpdatedAt         time.Time `json:"updatedAt"`         // When the record last changed
+}
+
+// VAAStore persists VAA delivery state so restarts neither lose nor re-send deliveries
+type VAAStore interface {
+	// Get returns the record for key, or ErrVAANotFound
+	Get(key string) (*VAARecord, error)
+	// Update atomically applies fn to the record for key, creating it if needed
+	Update(key string, fn func(rec *VAARecord) error) error
+	// List returns all records in one of the given states, or every record if none are given
+	List(statuses ...VAAStatus) ([]*VAARecord, error)
+	// ListEmitter returns the records of one emitter with a sequence in [from, to], lowest first
+	ListEmitter(chainID uint16, emitterHex string, from, to uint64) ([]*VAARecord, error)
+	// Prune deletes confirmed records last updated before cutoff and returns how many it deleted
+	Prune(cutoff time.Time) (int, error)
+	// Check reports whether the store can be read
+	Check() error
+	// Close releases the underlying storage
+	Close() error
+}
+
+var (
+	vaaBucket     = []byte("vaas")
+	statusBucket  = []byte("vaasByStatus")  // "<status>/<key>"
+	emitterBucket = []byte("vaasByEmitter") // "<chain>/<emitter>/<zero-padded sequence>/<key>"
+)
+
+// BoltVAAStore is a VAAStore backed by an embedded bbolt database file. Index buckets by status
+// and by emitter sequence keep the scheduler and lookups from decoding every record.
+type BoltVAAStore struct {
+	db *bolt.DB
+}
+
+// NewBoltVAAStore opens (or creates) the bbolt database at path
+func NewBoltVAAStore(path string) (*BoltVAAStore, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open VAA store %s: %v", path, err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		if _, err := tx.CreateBucketIfNotExists(vaaBucket); err != nil {
+			return err
+		}
+		if tx.Bucket(statusBucket) != nil {
+			return nil
+		}
+		// Databases written before the indexes existed are indexed once
+		return reindex(tx)
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize VAA store: %v", err)
+	}
+
+	return &BoltVAAStore{db: db}, nil
+}
+
+// Get returns the record for key, or ErrVAANotFound
+func (s *BoltVAAStore) Get(key string) (*VAARecord, error) {
+	var rec *VAARecord
+	err := s.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(vaaBucket).Get([]byte(key))
+		if data == nil {
+			return ErrVAANotFound
+		}
+		rec = &VAARecord{}
+		return json.Unmarshal(data, rec)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rec, nil
+}
+
+// Update atomically applies fn to the record for key, creating it if needed
+func (s *BoltVAAStore) Update(key string, fn func(rec *VAARecord) error) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(vaaBucket)
+
+		rec := &VAARecord{Key: key}
+		if data := bucket.Get([]byte(key)); data != nil {
+			if err := json.Unmarshal(data, rec); err != nil {
+				return fmt.Errorf("failed to decode VAA record %s: %v", key, err)
+			}
+			if err := unindexRecord(tx, rec); err != nil {
+				return err
+			}
+		}
+
+		if err := fn(rec); err != nil {
+			return err
+		}
+		touchRecord(rec)
+
+		data, err := json.Marshal(rec)
+		if err != nil {
+			return fmt.Errorf("failed to encode VAA record %s: %v", key, err)
+		}
+		if err := bucket.Put([]byte(key), data); err != nil {
+			return err
+		}
+		return indexRecord(tx, rec)
+	})
+}
+
+// List returns all records in one of the given states, or every record if none are given
+func (s *BoltVAAStore) List(statuses ...VAAStatus) ([]*VAARecord, error) {
+	var records []*VAARecord
+	err := s.db.View(func(tx *bolt.Tx) error {
+		if len(statuses) == 0 {
+			return tx.Bucket(vaaBucket).ForEach(func(k, v []byte) error {
+				rec, err := decodeRecord(k, v)
+				if err != nil {
+					return err
+				}
+				records = append(records, rec)
+				return nil
+			})
+		}
+		for _, status := range statuses {
+			err := scanIndex(tx, statusBucket, []byte(string(status)+"/"), func(rec *VAARecord) bool {
+				records = append(records, rec)
+				return true
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sortRecords(records)
+	return records, nil
+}
+
+// ListEmitter returns the records of one emitter with a sequence in [from, to], lowest first
+func (s *BoltVAAStore) ListEmitter(chainID uint16, emitterHex string, from, to uint64) ([]*VAARecord, error) {
+	var records []*VAARecord
+	err := s.db.View(func(tx *bolt.Tx) error {
+		prefix := emitterIndexPrefix(chainID, emitterHex)
+		start := append(append([]byte(nil), prefix...), fmt.Sprintf("%020d/", from)...)
+		c := tx.Bucket(emitterBucket).Cursor()
+		for k, v := c.Seek(start); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+			rec, err := indexedRecord(tx, k, v)
+			if err != nil {
+				return err
+			}
+			if rec.Sequence > to {
+				break
+			}
+			records = append(records, rec)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// Prune deletes confirmed records last updated before cutoff and returns how many it deleted
+func (s *BoltVAAStore) Prune(cutoff time.Time) (int, error) {
+	pruned := 0
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		var expired []*VAARecord
+		err := scanIndex(tx, statusBucket, []byte(string(VAAStatusConfirmed)+"/"), func(rec *VAARecord) bool {
+			if rec.UpdatedAt.Before(cutoff) {
+				expired = append(expired, rec)
+			}
+			return true
+		})
+		if err != nil {
+			return err
+		}
+		for _, rec := range expired {
+			if err := unindexRecord(tx, rec); err != nil {
+				return err
+			}
+			if err := tx.Bucket(vaaBucket).Delete([]byte(rec.Key)); err != nil {
+				return err
+			}
+		}
+		pruned = len(expired)
+		return nil
+	})
+	return pruned, err
+}
+
+// Close closes the database file
+// Check reads the database, failing once it is closed or unreadable
+func (s *BoltVAAStore) Check() error {
+	return s.db.View(func(tx *bolt.Tx) error {
+		if tx.Bucket(vaaBucket) == nil {
+			return errors.New("vaa bucket missing")
+		}
+		return nil
+	})
+}
+
+func (s *BoltVAAStore) Close() error {
+	return s.db.Close()
+}
+
+// decodeRecord decodes the stored record under key
+func decodeRecord(key, data []byte) (*VAARecord, error) {
+	rec := &VAARecord{}
+	if err := json.Unmarshal(data, rec); err != nil {
+		return nil, fmt.Errorf("failed to decode VAA record %s: %v", key, err)
+	}
+	return rec, nil
+}
+
+// indexedRecord loads the record an index entry points at
+func indexedRecord(tx *bolt.Tx, indexKey, key []byte) (*VAARecord, error) {
+	data := tx.Bucket(vaaBucket).Get(key)
+	if data == nil {
+		return nil, fmt.Errorf("index entry %s points at missing VAA record %s", indexKey, key)
+	}
+	return decodeRecord(key, data)
+}
+
+// scanIndex calls fn with the record of every entry of bucket under prefix until fn returns false
+func scanIndex(tx *bolt.Tx, bucket, prefix []byte, fn func(rec *VAARecord) bool) error {
+	c := tx.Bucket(bucket).Cursor()
+	for k, v := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+		rec, err := indexedRecord(tx, k, v)
+		if err != nil {
+			return err
+		}
+		if !fn(rec) {
+			return nil
+		}
+	}
+	return nil
+}
+
+func emitterIndexPrefix(chainID uint16, emitterHex string) []byte {
+	return []byte(fmt.Sprintf("%d/%s/", chainID, emitterHex))
+}
+
+// indexKeys returns the status and emitter index keys of rec
+func indexKeys(rec *VAARecord) (status, emitter []byte) {
+	status = []byte(string(rec.Status) + "/" + rec.Key)
+	emitter = append(emitterIndexPrefix(rec.ChainID, rec.EmitterHex), fmt.Sprintf("%020d/%s", rec.Sequence, rec.Key)...)
+	return status, emitter
+}
+
+func indexRecord(tx *bolt.Tx, rec *VAARecord) error {
+	status, emitter := indexKeys(rec)
+	if err := tx.Bucket(statusBucket).Put(status, []byte(rec.Key)); err != nil {
+		return err
+	}
+	return tx.Bucket(emitterBucket).Put(emitter, []byte(rec.Key))
+}
+
+func unindexRecord(tx *bolt.Tx, rec *VAARecord) error {
+	status, emitter := indexKeys(rec)
+	if err := tx.Bucket(statusBucket).Delete(status); err != nil {
+		return err
+	}
+	return tx.Bucket(emitterBucket).Delete(emitter)
+}
+
+// reindex creates the index buckets and fills them from the stored records
+func reindex(tx *bolt.Tx) error {
+	for _, name := range [][]byte{statusBucket, emitterBucket} {
+		if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+			return err
+		}
+	}
+	return tx.Bucket(vaaBucket).ForEach(func(k, v []byte) error {
+		rec, err := decodeRecord(k, v)
+		if err != nil {
+			return err
+		}
+		return indexRecord(tx, rec)
+	})
+}
+
+// MemoryVAAStore is a non-durable VAAStore for tests, injected with WithVAAStore
+type MemoryVAAStore struct {
+	mu      sync.Mutex
+	records map[string]VAARecord
+}
+
+// NewMemoryVAAStore creates an empty in-memory store
+func NewMemoryVAAStore() *MemoryVAAStore {
+	return &MemoryVAAStore{records: make(map[string]VAARecord)}
+}
+
+// Get returns the record for key, or ErrVAANotFound
+func (s *MemoryVAAStore) Get(key string) (*VAARecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, ok := s.records[key]
+	if !ok {
+		return nil, ErrVAANotFound
+	}
+	return copyRecord(rec), nil
+}
+
+// Update atomically applies fn to the record for key, creating it if needed
+func (s *MemoryVAAStore) Update(key string, fn func(rec *VAARecord) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec := &VAARecord{Key: key}
+	if existing, ok := s.records[key]; ok {
+		rec = copyRecord(existing)
+	}
+
+	if err := fn(rec); err != nil {
+		return err
+	}
+	touchRecord(rec)
+
+	s.records[key] = *copyRecord(*rec)
+	return nil
+}
+
+// List returns all records in one of the given states, or every record if none are given
+func (s *MemoryVAAStore) List(statuses ...VAAStatus) ([]*VAARecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var records []*VAARecord
+	for _, rec := range s.records {
+		if matchesStatus(&rec, statuses) {
+			records = append(records, copyRecord(rec))
+		}
+	}
+
+	sortRecords(records)
+	return records, nil
+}
+
+// ListEmitter returns the records of one emitter with a sequence in [from, to], lowest first
+func (s *MemoryVAAStore) ListEmitter(chainID uint16, emitterHex string, from, to uint64) ([]*VAARecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var records []*VAARecord
+	for _, rec := range s.records {
+		if rec.ChainID == chainID && rec.EmitterHex == emitterHex && rec.Sequence >= from && rec.Sequence <= to {
+			records = append(records, copyRecord(rec))
+		}
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Sequence < records[j].Sequence
+	})
+	return records, nil
+}
+
+// Prune deletes confirmed records last updated before cutoff and returns how many it deleted
+func (s *MemoryVAAStore) Prune(cutoff time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pruned := 0
+	for key, rec := range s.records {
+		if rec.Status == VAAStatusConfirmed && rec.UpdatedAt.Before(cutoff) {
+			delete(s.records, key)
+			pruned++
+		}
+	}
+	return pruned, nil
+}
+
+// Close is a no-op for the in-memory store
+// Check always succeeds for the in-memory store
+func (s *MemoryVAAStore) Check() error {
+	return nil
+}
+
+func (s *MemoryVAAStore) Close() error {
+	return nil
+}
+
+func touchRecord(rec *VAARecord) {
+	now := time.Now()
+	if rec.CreatedAt.IsZero() {
+		rec.CreatedAt = now
+	}
+	rec.UpdatedAt = now
+}
+
+func copyRecord(rec VAARecord) *VAARecord {
+	rec.RawBytes = append([]byte(nil), rec.RawBytes...)
+	rec.TxHashes = append([]string(nil), rec.TxHashes...)
+	return &rec
+}
+
+func matchesStatus(rec *VAARecord, statuses []VAAStatus) bool {
+	if len(statuses) == 0 {
+		return true
+	}
+	for _, status := range statuses {
+		if rec.Status == status {
+			return true
+		}
+	}
+	return false
+}
+
+// sortRecords orders records by receive time so resumed work keeps arrival order
+func sortRecords(records []*VAARecord) {
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].CreatedAt.Before(records[j].CreatedAt)
+	})
+}
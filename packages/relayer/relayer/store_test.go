@@ -0,0 +1,110 @@
+package relayer
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// storeRecord writes a record with the given emitter, sequence and status
+func storeRecord(t *testing.T, store VAAStore, key string, sequence uint64, status VAAStatus) {
+	t.Helper()
+	err := store.Update(key, func(rec *VAARecord) error {
+		rec.ChainID, rec.EmitterHex, rec.Sequence, rec.Status = 2, "emitter", sequence, status
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func recordKeys(records []*VAARecord) []string {
+	var keys []string
+	for _, rec := range records {
+		keys = append(keys, rec.Key)
+	}
+	return keys
+}
+
+func TestVAAStoreIndexes(t *testing.T) {
+	boltStore, err := NewBoltVAAStore(filepath.Join(t.TempDir(), "relayer.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer boltStore.Close()
+
+	for name, store := range map[string]VAAStore{"bolt": boltStore, "memory": NewMemoryVAAStore()} {
+		t.Run(name, func(t *testing.T) {
+			storeRecord(t, store, "c", 12, VAAStatusReceived)
+			storeRecord(t, store, "a", 10, VAAStatusConfirmed)
+			storeRecord(t, store, "b", 11, VAAStatusFailed)
+			storeRecord(t, store, "b", 11, VAAStatusSubmitted) // Moves between status indexes
+
+			if failed, _ := store.List(VAAStatusFailed); len(failed) != 0 {
+				t.Errorf("List(failed) = %v, want none", recordKeys(failed))
+			}
+			if submitted, _ := store.List(VAAStatusSubmitted); len(submitted) != 1 || submitted[0].Key != "b" {
+				t.Errorf("List(submitted) = %v, want [b]", recordKeys(submitted))
+			}
+
+			records, err := store.ListEmitter(2, "emitter", 11, 12)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := recordKeys(records); len(got) != 2 || got[0] != "b" || got[1] != "c" {
+				t.Errorf("ListEmitter(11, 12) = %v, want [b c]", got)
+			}
+			if records, _ := store.ListEmitter(3, "emitter", 0, 100); len(records) != 0 {
+				t.Errorf("ListEmitter of another chain = %v, want none", recordKeys(records))
+			}
+
+			pruned, err := store.Prune(time.Now().Add(time.Minute))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if pruned != 1 {
+				t.Errorf("Prune deleted %d records, want 1", pruned)
+			}
+			if _, err := store.Get("a"); err != ErrVAANotFound {
+				t.Errorf("Get of a pruned record: %v, want ErrVAANotFound", err)
+			}
+			if all, _ := store.List(); len(all) != 2 {
+				t.Errorf("List() = %v after pruning, want the two unfinished records", recordKeys(all))
+			}
+		})
+	}
+}
+
+func TestBoltVAAStoreIndexesExistingDatabase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "relayer.db")
+	store, err := NewBoltVAAStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	storeRecord(t, store, "a", 10, VAAStatusFailed)
+	// Drop the indexes, as in a database written before they existed
+	err = store.db.Update(func(tx *bolt.Tx) error {
+		if err := tx.DeleteBucket(statusBucket); err != nil {
+			return err
+		}
+		return tx.DeleteBucket(emitterBucket)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.Close()
+
+	store, err = NewBoltVAAStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	if failed, _ := store.List(VAAStatusFailed); len(failed) != 1 {
+		t.Errorf("List(failed) = %v after reopening, want [a]", recordKeys(failed))
+	}
+	if records, _ := store.ListEmitter(2, "emitter", 0, 100); len(records) != 1 {
+		t.Errorf("ListEmitter = %v after reopening, want [a]", recordKeys(records))
+	}
+}
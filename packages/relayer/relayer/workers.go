@@ -0,0 +1,127 @@
+package relayer
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	vaaLib "github.com/wormhole-foundation/wormhole/sdk/vaa"
+	"go.uber.org/zap"
+)
+
+// defaultWorkerCount is used when WorkerOptions.Count is not set
+const defaultWorkerCount = 8
+
+// WorkerOptions bounds how many VAAs are delivered at once and how many may wait for a worker
+type WorkerOptions struct {
+	Count     int // Deliveries processed concurrently
+	QueueSize int // VAAs waiting for a worker; when full, reading from the spy stream pauses
+}
+
+// workItem is a VAA waiting in the work queue
+type workItem struct {
+	key        string
+	vaaBytes   []byte
+	enqueuedAt time.Time
+}
+
+// startWorkers starts the worker pool draining the work queue until ctx is cancelled. VAAs
+// still queued at that point were recorded by dispatchVAA and are resumed on the next start.
+func (r *Relayer) startWorkers(ctx context.Context, wg *sync.WaitGroup) {
+	count := r.config.Workers.Count
+	if count <= 0 {
+		count = defaultWorkerCount
+	}
+	r.logger.Info("Starting delivery workers",
+		zap.Int("workers", count),
+		zap.Int("queueSize", cap(r.queue)))
+
+	for i := 0; i < count; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case item := <-r.queue:
+					workQueueDepth.Set(float64(len(r.queue)))
+					workQueueWait.Observe(time.Since(item.enqueuedAt).Seconds())
+					err := r.processVAA(ctx, item.key, item.vaaBytes)
+					r.finishProcessingVAA(item.key, err)
+				}
+			}
+		}()
+	}
+}
+
+// dispatchVAA queues a VAA claimed with beginProcessingVAA for a worker. It blocks while the
+// queue is full, which is what pauses the Start loop's reads from the spy. If ctx ends first
+// the claim is released and the VAA is left for a later attempt.
+func (r *Relayer) dispatchVAA(ctx context.Context, vaaBytes []byte, key string) {
+	r.recordQueued(vaaBytes, key)
+	item := workItem{key: key, vaaBytes: vaaBytes, enqueuedAt: time.Now()}
+
+	select {
+	case r.queue <- item:
+	default:
+		r.logger.Debug("Work queue full, waiting for a worker", zap.String("vaaHash", key))
+		blockedAt := time.Now()
+		defer func() { workQueueBlockedSeconds.Add(time.Since(blockedAt).Seconds()) }()
+		select {
+		case r.queue <- item:
+		case <-ctx.Done():
+			r.finishProcessingVAA(key, context.Canceled)
+			return
+		}
+	}
+	workQueueDepth.Set(float64(len(r.queue)))
+}
+
+// dispatchInBackground queues a VAA claimed with beginProcessingVAA from a goroutine Start waits
+// for, so a caller is not held up while the work queue is full
+func (r *Relayer) dispatchInBackground(vaaBytes []byte, key string) {
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		r.dispatchVAA(r.dispatchCtx, vaaBytes, key)
+	}()
+}
+
+// tryDispatchVAA queues a VAA claimed with beginProcessingVAA without waiting for room. If the
+// queue is full the claim is released and it returns false.
+func (r *Relayer) tryDispatchVAA(vaaBytes []byte, key string) bool {
+	r.recordQueued(vaaBytes, key)
+	select {
+	case r.queue <- workItem{key: key, vaaBytes: vaaBytes, enqueuedAt: time.Now()}:
+		workQueueDepth.Set(float64(len(r.queue)))
+		return true
+	default:
+		r.finishProcessingVAA(key, context.Canceled)
+		return false
+	}
+}
+
+// recordQueued stores a VAA some route delivers as received before it is queued, so it is
+// resumed on the next start if the relayer stops before a worker picks it up
+func (r *Relayer) recordQueued(vaaBytes []byte, key string) {
+	v, err := vaaLib.Unmarshal(vaaBytes)
+	if err != nil {
+		return
+	}
+	vaaData := &VAAData{
+		VAA:        v,
+		RawBytes:   vaaBytes,
+		ChainID:    uint16(v.EmitterChain),
+		EmitterHex: v.EmitterAddress.String(),
+		Sequence:   v.Sequence,
+		Key:        key,
+	}
+	// The processor decides what to record for VAAs no route delivers
+	if _, reason := r.matchRoute(vaaData.ChainID, vaaData.EmitterHex, v.Payload); reason != "" {
+		return
+	}
+	if err := r.recordReceived(vaaData); err != nil {
+		r.logger.Error("Failed to record queued VAA", zap.String("vaaHash", key), zap.Error(err))
+	}
+}
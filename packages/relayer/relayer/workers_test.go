@@ -0,0 +1,86 @@
+package relayer
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWorkerPoolBoundsConcurrencyAndPausesTheSpy(t *testing.T) {
+	var (
+		mu          sync.Mutex
+		running     int
+		maxRunning  int
+		processed   int
+		release     = make(chan struct{})
+		releaseOnce sync.Once
+	)
+	defer releaseOnce.Do(func() { close(release) })
+	processor := func(r *Relayer, vaaData *VAAData) error {
+		mu.Lock()
+		running++
+		maxRunning = max(maxRunning, running)
+		mu.Unlock()
+		<-release
+		mu.Lock()
+		running--
+		processed++
+		mu.Unlock()
+		return nil
+	}
+	f := startFakeRun(t, func(fc *fileConfig) {
+		fc.Workers.Count = 2
+		fc.Workers.QueueSize = 1
+	}, WithVAAProcessor(processor))
+
+	for i := 0; i < 6; i++ {
+		f.source.Publish(f.aztecVAA())
+	}
+
+	// Two VAAs with the workers, one queued and one held by the blocked spy loop
+	claimed := func() int {
+		f.relayer.dedupeMu.Lock()
+		defer f.relayer.dedupeMu.Unlock()
+		return len(f.relayer.inflightVAAs)
+	}
+	waitFor(t, "the work queue to fill", func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return running == 2 && len(f.relayer.queue) == 1 && claimed() == 4
+	})
+	time.Sleep(100 * time.Millisecond)
+	if n := claimed(); n != 4 {
+		t.Fatalf("relayer read %d VAAs while the queue was full, want 4", n)
+	}
+	// Queued VAAs are recorded, so a shutdown now would resume them on the next start
+	if received, _ := f.relayer.store.List(VAAStatusReceived); len(received) != 4 {
+		t.Errorf("%d claimed VAAs are recorded as received, want 4", len(received))
+	}
+
+	// The admin API turns VAAs away instead of waiting for room
+	body := fmt.Sprintf(`{"vaa": "%x"}`, f.aztecVAA())
+	w := httptest.NewRecorder()
+	f.relayer.handleSubmitVAA(w, httptest.NewRequest(http.MethodPost, "/admin/vaas", strings.NewReader(body)))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("admin submit with a full queue returned %d, want 503", w.Code)
+	}
+	if n := claimed(); n != 4 {
+		t.Errorf("%d VAAs claimed after the rejected submit, want its claim released", n)
+	}
+
+	releaseOnce.Do(func() { close(release) })
+	waitFor(t, "all VAAs to be processed", func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return processed == 6
+	})
+	mu.Lock()
+	defer mu.Unlock()
+	if maxRunning != 2 {
+		t.Errorf("at most %d VAAs were processed at once, want 2", maxRunning)
+	}
+}
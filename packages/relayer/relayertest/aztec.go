@@ -0,0 +1,207 @@
+package relayertest
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// PXEServer is a fake Aztec PXE JSON-RPC server that records verify_vaa transactions
+type PXEServer struct {
+	server *httptest.Server
+
+	mu      sync.Mutex
+	sent    [][]byte
+	sendErr error
+}
+
+// NewPXEServer starts a fake PXE; it is stopped when the test ends
+func NewPXEServer(t testing.TB) *PXEServer {
+	t.Helper()
+
+	s := &PXEServer{}
+	rpcServer := rpc.NewServer()
+	if err := rpcServer.RegisterName("pxe", &pxeAPI{s}); err != nil {
+		t.Fatal(err)
+	}
+	if err := rpcServer.RegisterName("node", &nodeAPI{}); err != nil {
+		t.Fatal(err)
+	}
+	s.server = httptest.NewServer(rpcServer)
+	t.Cleanup(func() {
+		s.server.Close()
+		rpcServer.Stop()
+	})
+	return s
+}
+
+// URL returns the PXE endpoint
+func (s *PXEServer) URL() string {
+	return s.server.URL
+}
+
+// FailSends makes pxe_sendTransaction fail with err until called again with nil
+func (s *PXEServer) FailSends(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sendErr = err
+}
+
+// Sent returns the VAAs of the verify_vaa transactions sent so far
+func (s *PXEServer) Sent() [][]byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([][]byte(nil), s.sent...)
+}
+
+// pxeTransaction is the pxe_simulateTransaction and pxe_sendTransaction request
+type pxeTransaction struct {
+	ContractAddress string            `json:"contractAddress"`
+	FunctionName    string            `json:"functionName"`
+	Args            []json.RawMessage `json:"args"`
+	Origin          string            `json:"origin"`
+}
+
+// vaaBytes unpacks the padded VAA array and length arguments of verify_vaa
+func (tx pxeTransaction) vaaBytes() ([]byte, error) {
+	if tx.FunctionName != "verify_vaa" || len(tx.Args) != 2 {
+		return nil, fmt.Errorf("unexpected call %s with %d arguments", tx.FunctionName, len(tx.Args))
+	}
+	var length int
+	var values []int
+	if err := json.Unmarshal(tx.Args[0], &values); err != nil {
+		return nil, fmt.Errorf("invalid VAA argument: %v", err)
+	}
+	if err := json.Unmarshal(tx.Args[1], &length); err != nil {
+		return nil, fmt.Errorf("invalid length argument: %v", err)
+	}
+	if length > len(values) {
+		return nil, fmt.Errorf("length %d exceeds the %d-byte VAA argument", length, len(values))
+	}
+	vaaBytes := make([]byte, length)
+	for i, v := range values[:length] {
+		vaaBytes[i] = byte(v)
+	}
+	return vaaBytes, nil
+}
+
+type pxeAPI struct {
+	s *PXEServer
+}
+
+// SimulateTransaction implements pxe_simulateTransaction
+func (api *pxeAPI) SimulateTransaction(tx pxeTransaction) (map[string]interface{}, error) {
+	if _, err := tx.vaaBytes(); err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"success": true}, nil
+}
+
+// SendTransaction implements pxe_sendTransaction
+func (api *pxeAPI) SendTransaction(tx pxeTransaction) (map[string]interface{}, error) {
+	vaaBytes, err := tx.vaaBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	api.s.mu.Lock()
+	defer api.s.mu.Unlock()
+	if api.s.sendErr != nil {
+		return nil, api.s.sendErr
+	}
+	api.s.sent = append(api.s.sent, vaaBytes)
+	return map[string]interface{}{"txHash": fmt.Sprintf("0x%064x", len(api.s.sent))}, nil
+}
+
+type nodeAPI struct{}
+
+// GetBlock implements node_getBlock
+func (nodeAPI) GetBlock(number int) (map[string]interface{}, error) {
+	return map[string]interface{}{"number": number}, nil
+}
+
+// GetBlockNumber implements node_getBlockNumber
+func (nodeAPI) GetBlockNumber() (int, error) {
+	return 1, nil
+}
+
+// VerificationServer is a fake Aztec verification service that records the VAAs it verifies
+type VerificationServer struct {
+	server *httptest.Server
+
+	mu       sync.Mutex
+	verified [][]byte
+	failWith string
+}
+
+// NewVerificationServer starts a fake verification service; it is stopped when the test ends
+func NewVerificationServer(t testing.TB) *VerificationServer {
+	t.Helper()
+
+	s := &VerificationServer{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /health", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("POST /verify", s.handleVerify)
+	s.server = httptest.NewServer(mux)
+	t.Cleanup(s.server.Close)
+	return s
+}
+
+// URL returns the service base URL
+func (s *VerificationServer) URL() string {
+	return s.server.URL
+}
+
+// Fail makes verification fail with reason until called again with ""
+func (s *VerificationServer) Fail(reason string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failWith = reason
+}
+
+// Verified returns the VAAs verified so far
+func (s *VerificationServer) Verified() [][]byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([][]byte(nil), s.verified...)
+}
+
+func (s *VerificationServer) handleVerify(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		VAABytes string `json:"vaaBytes"`
+	}
+	response := map[string]interface{}{"success": false}
+	defer func() {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	}()
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		response["error"] = err.Error()
+		return
+	}
+	vaaBytes, err := hex.DecodeString(strings.TrimPrefix(request.VAABytes, "0x"))
+	if err != nil {
+		response["error"] = err.Error()
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.failWith != "" {
+		response["error"] = s.failWith
+		return
+	}
+	s.verified = append(s.verified, vaaBytes)
+	response["success"] = true
+	response["txHash"] = fmt.Sprintf("0x%064x", len(s.verified))
+}
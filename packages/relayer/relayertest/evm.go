@@ -0,0 +1,281 @@
+package relayertest
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/core/vm/program"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/eth/ethconfig"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// ChainID is the chain and network ID of every simulated chain
+const ChainID = 1337
+
+// blockInterval is how often Chain seals a block while mining
+const blockInterval = 100 * time.Millisecond
+
+// TreasuryAddress is where Chain places the Treasury stand-in
+var TreasuryAddress = common.HexToAddress("0x7E5A5b11aE2bC0fFeE0000000000000000000001")
+
+// treasuryABIJSON is the part of the Treasury ABI the relayer calls
+const treasuryABIJSON = `[{
+        "inputs": [{"internalType": "bytes", "name": "encodedVm", "type": "bytes"}],
+        "name": "verify",
+        "outputs": [],
+        "stateMutability": "nonpayable",
+        "type": "function"
+    }, {
+        "inputs": [
+            {"internalType": "uint16", "name": "emitterChainId", "type": "uint16"},
+            {"internalType": "bytes32", "name": "emitterAddress", "type": "bytes32"},
+            {"internalType": "uint64", "name": "sequence", "type": "uint64"}
+        ],
+        "name": "isMessageProcessed",
+        "outputs": [{"internalType": "bool", "name": "", "type": "bool"}],
+        "stateMutability": "view",
+        "type": "function"
+    }]`
+
+var treasuryABI = mustParseABI(treasuryABIJSON)
+
+func mustParseABI(s string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(s))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
+
+// Chain is a simulated EVM chain served over IPC, with a Treasury stand-in at
+// TreasuryAddress. It seals a block every blockInterval until PauseMining is called.
+type Chain struct {
+	backend *simulated.Backend
+	url     string
+
+	mu     sync.Mutex
+	paused bool
+	stop   chan struct{}
+	done   chan struct{}
+}
+
+// NewChain starts a simulated chain that funds each of accounts with 100 ether; it is
+// stopped when the test ends
+func NewChain(t testing.TB, accounts ...common.Address) *Chain {
+	t.Helper()
+
+	funds := new(big.Int).Mul(big.NewInt(100), big.NewInt(params.Ether))
+	alloc := types.GenesisAlloc{
+		TreasuryAddress: {Code: treasuryCode(), Balance: common.Big0},
+	}
+	for _, account := range accounts {
+		alloc[account] = types.Account{Balance: funds}
+	}
+
+	url := filepath.Join(t.TempDir(), "geth.ipc")
+	backend := simulated.NewBackend(alloc, func(nodeConf *node.Config, ethConf *ethconfig.Config) {
+		nodeConf.IPCPath = url
+	})
+
+	c := &Chain{
+		backend: backend,
+		url:     url,
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go c.mine()
+	t.Cleanup(c.Close)
+	return c
+}
+
+// URL returns the IPC endpoint to configure as the route's RPC URL
+func (c *Chain) URL() string {
+	return c.url
+}
+
+// Client returns a client of the chain
+func (c *Chain) Client() simulated.Client {
+	return c.backend.Client()
+}
+
+// PauseMining stops sealing blocks, leaving sent transactions pending
+func (c *Chain) PauseMining() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.paused = true
+}
+
+// ResumeMining starts sealing blocks again
+func (c *Chain) ResumeMining() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.paused = false
+}
+
+func (c *Chain) mine() {
+	defer close(c.done)
+
+	ticker := time.NewTicker(blockInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.stop:
+			return
+		case <-ticker.C:
+			c.mu.Lock()
+			if !c.paused {
+				c.backend.Commit()
+			}
+			c.mu.Unlock()
+		}
+	}
+}
+
+// Close stops mining and shuts the chain down
+func (c *Chain) Close() {
+	select {
+	case <-c.stop:
+		return
+	default:
+	}
+	close(c.stop)
+	<-c.done
+	c.backend.Close()
+}
+
+// IsMessageProcessed reads the Treasury stand-in's processed flag for a message
+func (c *Chain) IsMessageProcessed(ctx context.Context, emitterChain uint16, emitter [32]byte, sequence uint64) (bool, error) {
+	data, err := treasuryABI.Pack("isMessageProcessed", emitterChain, emitter, sequence)
+	if err != nil {
+		return false, err
+	}
+	result, err := c.Client().CallContract(ctx, ethereum.CallMsg{To: &TreasuryAddress, Data: data}, nil)
+	if err != nil {
+		return false, err
+	}
+	var processed bool
+	err = treasuryABI.UnpackIntoInterface(&processed, "isMessageProcessed", result)
+	return processed, err
+}
+
+// VerifyCalls returns how many verify() transactions have been mined, successful or not
+func (c *Chain) VerifyCalls(ctx context.Context) (int, error) {
+	head, err := c.Client().BlockNumber(ctx)
+	if err != nil {
+		return 0, err
+	}
+	selector := treasuryABI.Methods["verify"].ID
+
+	calls := 0
+	for n := uint64(1); n <= head; n++ {
+		block, err := c.Client().BlockByNumber(ctx, new(big.Int).SetUint64(n))
+		if err != nil {
+			return 0, err
+		}
+		for _, tx := range block.Transactions() {
+			if tx.To() != nil && *tx.To() == TreasuryAddress && len(tx.Data()) >= 4 &&
+				string(tx.Data()[:4]) == string(selector) {
+				calls++
+			}
+		}
+	}
+	return calls, nil
+}
+
+// NewKey generates an account key for the relayer to sign with
+func NewKey(t testing.TB) *ecdsa.PrivateKey {
+	t.Helper()
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	return key
+}
+
+// Calldata offsets of verify(bytes encodedVm): the selector, the offset of encodedVm and its
+// length come first, followed by the VAA itself
+const (
+	vaaCalldataOffset = 4 + 32 + 32
+	vaaSigCountOffset = vaaCalldataOffset + 5 // version (1) + guardian set index (4)
+	vaaBodyOffset     = vaaCalldataOffset + 6 // plus 66 bytes per signature
+	vaaSignatureSize  = 66
+	vaaEmitterOffset  = 8  // Body offset of emitter chain, emitter address and sequence
+	vaaEmitterSize    = 42 // 2 + 32 + 8 bytes, the same as abi.encodePacked(chain, emitter, sequence)
+)
+
+// treasuryCode assembles a stand-in for the Treasury with the two functions the relayer
+// calls. verify(bytes) marks the VAA's (emitter chain, emitter, sequence) processed and
+// reverts with "Message already processed" on a replay. Unlike the real contract it does not
+// check guardian signatures or pay out, which leaves the relayer's own checks observable.
+func treasuryCode() []byte {
+	// Jump targets are only known after assembly, so assemble until they stop moving
+	var labels, prev [3]uint64
+	for {
+		code := assembleTreasury(&labels)
+		if labels == prev {
+			return code
+		}
+		prev = labels
+	}
+}
+
+// assembleTreasury assembles the stand-in jumping to the given verify, isMessageProcessed and
+// revert targets, and updates them to where they ended up
+func assembleTreasury(labels *[3]uint64) []byte {
+	verifyAt, processedAt, revertAt := labels[0], labels[1], labels[2]
+	p := program.New()
+
+	// Dispatch on the selector
+	p.Push(0).Op(vm.CALLDATALOAD).Push(224).Op(vm.SHR)
+	p.Op(vm.DUP1).Push(treasuryABI.Methods["verify"].ID).Op(vm.EQ).Push(verifyAt).Op(vm.JUMPI)
+	p.Push(treasuryABI.Methods["isMessageProcessed"].ID).Op(vm.EQ).Push(processedAt).Op(vm.JUMPI)
+	p.Push(0).Push(0).Op(vm.REVERT)
+
+	// verify(bytes): hash the packed message ID straight out of the VAA body
+	_, labels[0] = p.Jumpdest()
+	p.Push(vaaSignatureSize).Push(vaaSigCountOffset).Op(vm.CALLDATALOAD).Push(248).Op(vm.SHR).Op(vm.MUL)
+	p.Push(vaaBodyOffset + vaaEmitterOffset).Op(vm.ADD)
+	p.Push(vaaEmitterSize).Op(vm.SWAP1).Push(0).Op(vm.CALLDATACOPY)
+	p.Push(vaaEmitterSize).Push(0).Op(vm.KECCAK256)
+	p.Op(vm.DUP1).Op(vm.SLOAD).Push(revertAt).Op(vm.JUMPI)
+	p.Push(1).Op(vm.SWAP1).Op(vm.SSTORE).Op(vm.STOP)
+
+	_, labels[2] = p.Jumpdest()
+	reason := revertReason("Message already processed")
+	p.Mstore(reason, 0).Push(len(reason)).Push(0).Op(vm.REVERT)
+
+	// isMessageProcessed(uint16,bytes32,uint64): pack the arguments and look up the flag
+	_, labels[1] = p.Jumpdest()
+	p.Push(4).Op(vm.CALLDATALOAD).Push(240).Op(vm.SHL).Push(0).Op(vm.MSTORE)
+	p.Push(36).Op(vm.CALLDATALOAD).Push(2).Op(vm.MSTORE)
+	p.Push(68).Op(vm.CALLDATALOAD).Push(192).Op(vm.SHL).Push(34).Op(vm.MSTORE)
+	p.Push(vaaEmitterSize).Push(0).Op(vm.KECCAK256).Op(vm.SLOAD).Push(0).Op(vm.MSTORE)
+	p.Return(0, 32)
+
+	return p.Bytes()
+}
+
+// revertReason ABI-encodes Error(reason) revert data
+func revertReason(reason string) []byte {
+	stringType, _ := abi.NewType("string", "", nil)
+	encoded, err := abi.Arguments{{Type: stringType}}.Pack(reason)
+	if err != nil {
+		panic(err)
+	}
+	return append(crypto.Keccak256([]byte("Error(string)"))[:4], encoded...)
+}
@@ -0,0 +1,392 @@
+package relayertest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"sync"
+
+	spyv1 "github.com/certusone/wormhole/node/pkg/proto/spy/v1"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	vaaLib "github.com/wormhole-foundation/wormhole/sdk/vaa"
+	"google.golang.org/grpc/metadata"
+)
+
+// The fakes below are in-memory stand-ins for the relayer's injectable dependencies, passed to
+// relayer.New with WithVAASource, WithEVMSubmitter, WithAztecSubmitter and WithVerificationService.
+// Unlike the servers above they skip the network entirely.
+
+// VAASource is a fake VAA source. VAAs published while no stream is open are buffered, and only
+// VAAs matching the current filters are delivered.
+type VAASource struct {
+	mu        sync.Mutex
+	pending   [][]byte
+	filters   []*spyv1.FilterEntry
+	changed   bool
+	streams   int
+	cancel    context.CancelFunc
+	published chan struct{}
+}
+
+// NewVAASource returns an empty fake VAA source
+func NewVAASource() *VAASource {
+	return &VAASource{published: make(chan struct{})}
+}
+
+// Publish queues a signed VAA for the subscriber
+func (s *VAASource) Publish(vaaBytes []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pending = append(s.pending, vaaBytes)
+	close(s.published)
+	s.published = make(chan struct{})
+}
+
+// Disconnect ends the active stream with an error, as if the spy went away
+func (s *VAASource) Disconnect() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cancel != nil {
+		s.cancel()
+	}
+}
+
+// Streams returns how many streams have been opened
+func (s *VAASource) Streams() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.streams
+}
+
+// Filters returns the filters last set by the relayer
+func (s *VAASource) Filters() []*spyv1.FilterEntry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.filters
+}
+
+// SubscribeSignedVAA opens a stream of the published VAAs, replacing any active stream
+func (s *VAASource) SubscribeSignedVAA(ctx context.Context) (spyv1.SpyRPCService_SubscribeSignedVAAClient, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cancel != nil {
+		s.cancel()
+	}
+	streamCtx, cancel := context.WithCancel(ctx)
+	s.cancel = cancel
+	s.changed = false
+	s.streams++
+	return &vaaStream{source: s, ctx: streamCtx}, nil
+}
+
+// SetFilters replaces the filters and ends the active stream
+func (s *VAASource) SetFilters(filters []*spyv1.FilterEntry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.filters = filters
+	if s.cancel != nil {
+		s.changed = true
+		s.cancel()
+	}
+}
+
+// FiltersChanged reports, once, whether the last stream was ended by SetFilters
+func (s *VAASource) FiltersChanged() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	changed := s.changed
+	s.changed = false
+	return changed
+}
+
+// Close ends the active stream
+func (s *VAASource) Close() {
+	s.Disconnect()
+}
+
+// next returns the next published VAA that matches the filters, waiting for one if needed
+func (s *VAASource) next(ctx context.Context) ([]byte, error) {
+	for {
+		s.mu.Lock()
+		for len(s.pending) > 0 {
+			vaaBytes := s.pending[0]
+			s.pending = s.pending[1:]
+			if matchesFilters(s.filters, vaaBytes) {
+				s.mu.Unlock()
+				return vaaBytes, nil
+			}
+		}
+		published := s.published
+		s.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-published:
+		}
+	}
+}
+
+// vaaStream is a client stream of a VAASource
+type vaaStream struct {
+	source *VAASource
+	ctx    context.Context
+}
+
+func (st *vaaStream) Recv() (*spyv1.SubscribeSignedVAAResponse, error) {
+	vaaBytes, err := st.source.next(st.ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &spyv1.SubscribeSignedVAAResponse{VaaBytes: vaaBytes}, nil
+}
+
+func (st *vaaStream) Header() (metadata.MD, error) { return nil, nil }
+func (st *vaaStream) Trailer() metadata.MD         { return nil }
+func (st *vaaStream) CloseSend() error             { return nil }
+func (st *vaaStream) Context() context.Context     { return st.ctx }
+func (st *vaaStream) SendMsg(m interface{}) error  { return errors.New("send on a receive-only stream") }
+func (st *vaaStream) RecvMsg(m interface{}) error  { return io.EOF }
+
+// EVMSubmitter is a fake EVM submitter that confirms every verify transaction as soon as its
+// receipt is awaited and remembers the delivered messages, so a replay is reported as already
+// processed
+type EVMSubmitter struct {
+	Address common.Address
+
+	mu         sync.Mutex
+	sent       [][]byte
+	txs        map[string]string // Message ID by transaction hash
+	cancelled  []string
+	processed  map[string]bool
+	sendErr    error
+	receiptErr error
+	balance    *big.Int
+}
+
+// NewEVMSubmitter returns a fake EVM submitter holding 100 ether
+func NewEVMSubmitter() *EVMSubmitter {
+	return &EVMSubmitter{
+		Address:   common.HexToAddress("0x00000000000000000000000000000000000000E1"),
+		txs:       make(map[string]string),
+		processed: make(map[string]bool),
+		balance:   new(big.Int).Mul(big.NewInt(100), big.NewInt(params.Ether)),
+	}
+}
+
+// FailSends makes SendVerifyTransaction fail with err until called again with nil
+func (s *EVMSubmitter) FailSends(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sendErr = err
+}
+
+// FailReceipts makes WaitForReceipt fail with err, leaving sent transactions unmined, until
+// called again with nil
+func (s *EVMSubmitter) FailReceipts(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.receiptErr = err
+}
+
+// SetBalance sets the balance reported for the sending account
+func (s *EVMSubmitter) SetBalance(balance *big.Int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.balance = balance
+}
+
+// Sent returns the VAAs of the verify transactions sent so far
+func (s *EVMSubmitter) Sent() [][]byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([][]byte(nil), s.sent...)
+}
+
+// GetAddress returns the fake sending account
+func (s *EVMSubmitter) GetAddress() common.Address {
+	return s.Address
+}
+
+// SendVerifyTransaction records the VAA; its message is processed once the receipt is awaited
+func (s *EVMSubmitter) SendVerifyTransaction(ctx context.Context, targetContract string, vaaBytes []byte) (string, error) {
+	v, err := vaaLib.Unmarshal(vaaBytes)
+	if err != nil {
+		return "", fmt.Errorf("invalid VAA: %v", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.sendErr != nil {
+		return "", s.sendErr
+	}
+	id := messageID(targetContract, uint16(v.EmitterChain), v.EmitterAddress, v.Sequence)
+	if s.processed[id] {
+		return "", errors.New("execution reverted: Message already processed")
+	}
+	s.sent = append(s.sent, vaaBytes)
+	txHash := common.BigToHash(big.NewInt(int64(len(s.sent)))).Hex()
+	s.txs[txHash] = id
+	return txHash, nil
+}
+
+// WaitForReceipt mines txHash and returns a successful receipt for it
+func (s *EVMSubmitter) WaitForReceipt(ctx context.Context, txHash string) (*types.Receipt, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.receiptErr != nil {
+		return nil, s.receiptErr
+	}
+	if id, ok := s.txs[txHash]; ok {
+		s.processed[id] = true
+	}
+	return &types.Receipt{
+		Status:      types.ReceiptStatusSuccessful,
+		TxHash:      common.HexToHash(txHash),
+		BlockNumber: big.NewInt(1),
+	}, nil
+}
+
+// IsMessageProcessed reports whether a verify transaction delivered the message
+func (s *EVMSubmitter) IsMessageProcessed(ctx context.Context, targetContract string, emitterChain uint16, emitterAddress [32]byte, sequence uint64) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.processed[messageID(targetContract, emitterChain, emitterAddress, sequence)], nil
+}
+
+// CancelTransaction drops a sent transaction whose message was not processed yet, so awaiting
+// it never delivers the message
+func (s *EVMSubmitter) CancelTransaction(ctx context.Context, txHash string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id, ok := s.txs[txHash]
+	if !ok || s.processed[id] {
+		return "", fmt.Errorf("transaction %s is not pending", txHash)
+	}
+	delete(s.txs, txHash)
+	s.cancelled = append(s.cancelled, txHash)
+	return fmt.Sprintf("0xca%062x", len(s.cancelled)), nil // Apart from the sent transactions' hashes
+}
+
+// Cancelled returns the hashes of the transactions cancelled so far
+func (s *EVMSubmitter) Cancelled() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.cancelled...)
+}
+
+// MonitorTransactions returns when ctx is cancelled; there is nothing to replace
+func (s *EVMSubmitter) MonitorTransactions(ctx context.Context) {
+	<-ctx.Done()
+}
+
+// Balance returns the balance set with SetBalance
+func (s *EVMSubmitter) Balance(ctx context.Context) (*big.Int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return new(big.Int).Set(s.balance), nil
+}
+
+// CheckHealth always succeeds
+func (s *EVMSubmitter) CheckHealth(ctx context.Context) (string, error) {
+	return "fake chain", nil
+}
+
+func messageID(targetContract string, emitterChain uint16, emitterAddress [32]byte, sequence uint64) string {
+	return fmt.Sprintf("%s/%d/%x/%d", targetContract, emitterChain, emitterAddress, sequence)
+}
+
+// AztecSubmitter is a fake Aztec submitter that records the VAAs it sends
+type AztecSubmitter struct {
+	WalletAddress string
+
+	mu      sync.Mutex
+	sent    [][]byte
+	sendErr error
+}
+
+// NewAztecSubmitter returns a fake Aztec submitter
+func NewAztecSubmitter() *AztecSubmitter {
+	return &AztecSubmitter{WalletAddress: "0x" + fmt.Sprintf("%064x", 0xa2)}
+}
+
+// FailSends makes SendVerifyTransaction fail with err until called again with nil
+func (s *AztecSubmitter) FailSends(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sendErr = err
+}
+
+// Sent returns the VAAs sent so far
+func (s *AztecSubmitter) Sent() [][]byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([][]byte(nil), s.sent...)
+}
+
+// SendVerifyTransaction records the VAA
+func (s *AztecSubmitter) SendVerifyTransaction(ctx context.Context, targetContract string, vaaBytes []byte) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.sendErr != nil {
+		return "", s.sendErr
+	}
+	s.sent = append(s.sent, vaaBytes)
+	return fmt.Sprintf("0x%064x", len(s.sent)), nil
+}
+
+// GetWalletAddress returns the fake wallet
+func (s *AztecSubmitter) GetWalletAddress() string {
+	return s.WalletAddress
+}
+
+// CheckHealth always succeeds
+func (s *AztecSubmitter) CheckHealth(ctx context.Context) (string, error) {
+	return "fake PXE", nil
+}
+
+// VerificationService is a fake verification service that records the VAAs it verifies
+type VerificationService struct {
+	mu       sync.Mutex
+	verified [][]byte
+	failWith error
+}
+
+// NewVerificationService returns a fake verification service
+func NewVerificationService() *VerificationService {
+	return &VerificationService{}
+}
+
+// Fail makes verification fail with err until called again with nil
+func (s *VerificationService) Fail(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failWith = err
+}
+
+// Verified returns the VAAs verified so far
+func (s *VerificationService) Verified() [][]byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([][]byte(nil), s.verified...)
+}
+
+// VerifyVAA records the VAA
+func (s *VerificationService) VerifyVAA(ctx context.Context, vaaBytes []byte) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.failWith != nil {
+		return "", s.failWith
+	}
+	s.verified = append(s.verified, vaaBytes)
+	return fmt.Sprintf("0x%064x", len(s.verified)), nil
+}
+
+// CheckHealth always succeeds
+func (s *VerificationService) CheckHealth(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,192 @@
+// Package relayertest provides in-process fakes of the services the relayer talks to, so the
+// full delivery path can be tested offline: a Wormhole spy, an EVM chain running a Treasury
+// stand-in, an Aztec PXE and the Aztec verification service.
+package relayertest
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+
+	spyv1 "github.com/certusone/wormhole/node/pkg/proto/spy/v1"
+	vaaLib "github.com/wormhole-foundation/wormhole/sdk/vaa"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// SpyServer is a fake spy service that streams scripted VAAs to its subscribers. Like the
+// real spy, it only sends VAAs that match a subscription's emitter filters.
+type SpyServer struct {
+	spyv1.UnimplementedSpyRPCServiceServer
+
+	listener net.Listener
+	server   *grpc.Server
+
+	mu            sync.Mutex
+	subscriptions map[*spySubscription]struct{}
+	pending       [][]byte // Published while nobody was subscribed, sent to the next subscriber
+	subscribed    int      // Subscriptions accepted so far
+	changed       chan struct{}
+}
+
+type spySubscription struct {
+	filters []*spyv1.FilterEntry
+	vaas    chan []byte
+	end     chan error
+}
+
+// NewSpyServer starts a fake spy on a loopback port; it is stopped when the test ends
+func NewSpyServer(t testing.TB) *SpyServer {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen for the fake spy: %v", err)
+	}
+	s := &SpyServer{
+		listener:      listener,
+		server:        grpc.NewServer(),
+		subscriptions: make(map[*spySubscription]struct{}),
+		changed:       make(chan struct{}),
+	}
+	spyv1.RegisterSpyRPCServiceServer(s.server, s)
+	go s.server.Serve(listener)
+	t.Cleanup(s.Close)
+	return s
+}
+
+// Addr returns the host:port to configure as the spy endpoint
+func (s *SpyServer) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// Publish streams vaaBytes to every subscription whose filters match it. If nobody is
+// subscribed, the VAA is held for the next subscriber.
+func (s *SpyServer) Publish(vaaBytes []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.subscriptions) == 0 {
+		s.pending = append(s.pending, vaaBytes)
+		return
+	}
+	for sub := range s.subscriptions {
+		if matchesFilters(sub.filters, vaaBytes) {
+			sub.vaas <- vaaBytes
+		}
+	}
+}
+
+// Disconnect ends every active stream with an Unavailable error, as a restarting spy would
+func (s *SpyServer) Disconnect() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for sub := range s.subscriptions {
+		sub.end <- status.Error(codes.Unavailable, "spy restarting")
+		delete(s.subscriptions, sub)
+	}
+	s.notify()
+}
+
+// Subscriptions returns how many subscriptions the server has accepted, including ended ones
+func (s *SpyServer) Subscriptions() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.subscribed
+}
+
+// WaitForSubscriptions blocks until at least n subscriptions have been accepted
+func (s *SpyServer) WaitForSubscriptions(ctx context.Context, n int) error {
+	for {
+		s.mu.Lock()
+		subscribed, changed := s.subscribed, s.changed
+		s.mu.Unlock()
+		if subscribed >= n {
+			return nil
+		}
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return fmt.Errorf("%d of %d spy subscriptions: %v", subscribed, n, ctx.Err())
+		}
+	}
+}
+
+// Close stops the server and ends all streams
+func (s *SpyServer) Close() {
+	s.server.Stop()
+}
+
+// SubscribeSignedVAA implements spyv1.SpyRPCServiceServer
+func (s *SpyServer) SubscribeSignedVAA(req *spyv1.SubscribeSignedVAARequest, stream spyv1.SpyRPCService_SubscribeSignedVAAServer) error {
+	sub := &spySubscription{
+		filters: req.Filters,
+		vaas:    make(chan []byte, 256),
+		end:     make(chan error, 1),
+	}
+
+	s.mu.Lock()
+	for _, vaaBytes := range s.pending {
+		if matchesFilters(sub.filters, vaaBytes) {
+			sub.vaas <- vaaBytes
+		}
+	}
+	s.pending = nil
+	s.subscriptions[sub] = struct{}{}
+	s.subscribed++
+	s.notify()
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.subscriptions, sub)
+		s.mu.Unlock()
+	}()
+
+	for {
+		select {
+		case vaaBytes := <-sub.vaas:
+			if err := stream.Send(&spyv1.SubscribeSignedVAAResponse{VaaBytes: vaaBytes}); err != nil {
+				return err
+			}
+		case err := <-sub.end:
+			return err
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		}
+	}
+}
+
+// notify wakes WaitForSubscriptions callers; s.mu must be held
+func (s *SpyServer) notify() {
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+// matchesFilters reports whether the VAA's emitter is in filters; no filters match everything
+func matchesFilters(filters []*spyv1.FilterEntry, vaaBytes []byte) bool {
+	if len(filters) == 0 {
+		return true
+	}
+	v, err := vaaLib.Unmarshal(vaaBytes)
+	if err != nil {
+		return false
+	}
+	for _, entry := range filters {
+		filter := entry.GetEmitterFilter()
+		if filter == nil {
+			continue
+		}
+		if uint16(filter.ChainId) == uint16(v.EmitterChain) &&
+			strings.EqualFold(strings.TrimPrefix(filter.EmitterAddress, "0x"), v.EmitterAddress.String()) {
+			return true
+		}
+	}
+	return false
+}
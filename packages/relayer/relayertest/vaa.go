@@ -0,0 +1,118 @@
+package relayertest
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	vaaLib "github.com/wormhole-foundation/wormhole/sdk/vaa"
+)
+
+// Guardians is a test guardian set that signs VAAs
+type Guardians struct {
+	Index uint32
+	keys  []*ecdsa.PrivateKey
+}
+
+// NewGuardians generates a guardian set of n keys with the given index
+func NewGuardians(t testing.TB, index uint32, n int) *Guardians {
+	t.Helper()
+
+	g := &Guardians{Index: index}
+	for i := 0; i < n; i++ {
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			t.Fatalf("failed to generate guardian key: %v", err)
+		}
+		g.keys = append(g.keys, key)
+	}
+	return g
+}
+
+// Addresses returns the guardian addresses in set order
+func (g *Guardians) Addresses() []common.Address {
+	addresses := make([]common.Address, len(g.keys))
+	for i, key := range g.keys {
+		addresses[i] = crypto.PubkeyToAddress(key.PublicKey)
+	}
+	return addresses
+}
+
+// WriteSetFile writes the set in the relayer's guardian set file format and returns the path
+func (g *Guardians) WriteSetFile(t testing.TB) string {
+	t.Helper()
+
+	data, err := json.Marshal(map[string]interface{}{
+		"index": g.Index,
+		"keys":  g.Addresses(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "guardian-set.json")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// NewVAA returns an unsigned VAA for the guardian set
+func (g *Guardians) NewVAA(emitterChain uint16, emitter vaaLib.Address, sequence uint64, payload []byte) *vaaLib.VAA {
+	return &vaaLib.VAA{
+		Version:          vaaLib.SupportedVAAVersion,
+		GuardianSetIndex: g.Index,
+		Timestamp:        time.Unix(time.Now().Unix(), 0),
+		Nonce:            1,
+		Sequence:         sequence,
+		ConsistencyLevel: 1,
+		EmitterChain:     vaaLib.ChainID(emitterChain),
+		EmitterAddress:   emitter,
+		Payload:          payload,
+	}
+}
+
+// Sign signs v with every guardian key and returns the serialized VAA
+func (g *Guardians) Sign(t testing.TB, v *vaaLib.VAA) []byte {
+	t.Helper()
+
+	for i, key := range g.keys {
+		v.AddSignature(key, uint8(i))
+	}
+	return marshalVAA(t, v)
+}
+
+// SignBy signs v with the guardians at the given positions in the set and returns the
+// serialized VAA
+func (g *Guardians) SignBy(t testing.TB, v *vaaLib.VAA, positions ...int) []byte {
+	t.Helper()
+
+	for _, i := range positions {
+		v.AddSignature(g.keys[i], uint8(i))
+	}
+	return marshalVAA(t, v)
+}
+
+// SignWith signs v with keys outside the guardian set, producing a VAA that must be rejected
+func SignWith(t testing.TB, v *vaaLib.VAA, keys ...*ecdsa.PrivateKey) []byte {
+	t.Helper()
+
+	for i, key := range keys {
+		v.AddSignature(key, uint8(i))
+	}
+	return marshalVAA(t, v)
+}
+
+func marshalVAA(t testing.TB, v *vaaLib.VAA) []byte {
+	t.Helper()
+
+	vaaBytes, err := v.Marshal()
+	if err != nil {
+		t.Fatalf("failed to marshal VAA: %v", err)
+	}
+	return vaaBytes
+}
@@ -0,0 +1,75 @@
+package relayertest
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	vaaLib "github.com/wormhole-foundation/wormhole/sdk/vaa"
+)
+
+// WormholescanServer is a fake Wormholescan API serving GET /v1/signed_vaa for the VAAs added to it
+type WormholescanServer struct {
+	server *httptest.Server
+
+	mu       sync.Mutex
+	vaas     map[string][]byte
+	requests int
+}
+
+// NewWormholescanServer starts a fake Wormholescan API; it is stopped when the test ends
+func NewWormholescanServer(t testing.TB) *WormholescanServer {
+	t.Helper()
+
+	s := &WormholescanServer{vaas: make(map[string][]byte)}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/signed_vaa/{chain}/{emitter}/{sequence}", s.handleSignedVAA)
+	s.server = httptest.NewServer(mux)
+	t.Cleanup(s.server.Close)
+	return s
+}
+
+// URL returns the API base URL
+func (s *WormholescanServer) URL() string {
+	return s.server.URL
+}
+
+// Add makes a signed VAA available
+func (s *WormholescanServer) Add(t testing.TB, vaaBytes []byte) {
+	t.Helper()
+
+	v, err := vaaLib.Unmarshal(vaaBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.vaas[fmt.Sprintf("%d/%s/%d", v.EmitterChain, v.EmitterAddress, v.Sequence)] = vaaBytes
+}
+
+// Requests returns how many signed VAAs have been requested
+func (s *WormholescanServer) Requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests
+}
+
+func (s *WormholescanServer) handleSignedVAA(w http.ResponseWriter, r *http.Request) {
+	id := fmt.Sprintf("%s/%s/%s", r.PathValue("chain"), r.PathValue("emitter"), r.PathValue("sequence"))
+
+	s.mu.Lock()
+	s.requests++
+	vaaBytes, ok := s.vaas[id]
+	s.mu.Unlock()
+
+	if !ok {
+		http.Error(w, `{"message":"not found"}`, http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"vaaBytes": base64.StdEncoding.EncodeToString(vaaBytes)})
+}
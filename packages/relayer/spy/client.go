@@ -0,0 +1,126 @@
+// Package spy is a client of the Wormhole spy, which streams signed VAAs from the guardian
+// network filtered by emitter.
+package spy
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	spyv1 "github.com/certusone/wormhole/node/pkg/proto/spy/v1"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// Client handles connections to the Wormhole spy service
+type Client struct {
+	conn   *grpc.ClientConn
+	client spyv1.SpyRPCServiceClient
+	logger *zap.Logger
+	// Server-side filters for new subscriptions; changing them ends the active stream.
+	filtersMu      sync.Mutex
+	filters        []*spyv1.FilterEntry
+	cancelStream   context.CancelFunc
+	filtersChanged bool
+}
+
+// NewClient creates a new client for the Wormhole spy service
+func NewClient(endpoint string, logger *zap.Logger) (*Client, error) {
+	client := &Client{
+		logger: logger.With(zap.String("component", "SpyClient")),
+	}
+
+	client.logger.Info("Connecting to spy service", zap.String("endpoint", endpoint))
+	conn, err := grpc.Dial(endpoint, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to spy: %v", err)
+	}
+
+	client.conn = conn
+	client.client = spyv1.NewSpyRPCServiceClient(conn)
+	return client, nil
+}
+
+// Close closes the connection to the spy service
+func (c *Client) Close() {
+	if c.conn != nil {
+		c.conn.Close()
+	}
+}
+
+// SetFilters replaces the emitter filters and ends the active stream, so the caller
+// resubscribes with the new filters
+func (c *Client) SetFilters(filters []*spyv1.FilterEntry) {
+	c.filtersMu.Lock()
+	defer c.filtersMu.Unlock()
+
+	c.filters = filters
+	if c.cancelStream != nil {
+		c.filtersChanged = true
+		c.cancelStream()
+	}
+}
+
+// FiltersChanged reports, once, whether the last stream was ended by SetFilters
+func (c *Client) FiltersChanged() bool {
+	c.filtersMu.Lock()
+	defer c.filtersMu.Unlock()
+
+	changed := c.filtersChanged
+	c.filtersChanged = false
+	return changed
+}
+
+// streamRequest returns the subscribe request for the current filters and a stream
+// context that SetFilters can cancel
+func (c *Client) streamRequest(ctx context.Context) (context.Context, *spyv1.SubscribeSignedVAARequest) {
+	c.filtersMu.Lock()
+	defer c.filtersMu.Unlock()
+
+	if c.cancelStream != nil {
+		c.cancelStream()
+	}
+	streamCtx, cancel := context.WithCancel(ctx)
+	c.cancelStream = cancel
+	c.filtersChanged = false
+
+	return streamCtx, &spyv1.SubscribeSignedVAARequest{Filters: c.filters}
+}
+
+// SubscribeSignedVAA subscribes to signed VAAs matching the current filters with retry logic.
+// Every subscription shares the client's connection, which gRPC re-establishes when it drops,
+// so resubscribing after a filter change does not open another connection.
+func (c *Client) SubscribeSignedVAA(ctx context.Context) (spyv1.SpyRPCService_SubscribeSignedVAAClient, error) {
+	const maxRetries = 5
+	const retryDelay = 2 * time.Second
+
+	c.logger.Debug("Subscribing to signed VAAs")
+
+	var err error
+	for attempt := 1; attempt <= maxRetries; attempt++ {
+		var stream spyv1.SpyRPCService_SubscribeSignedVAAClient
+		streamCtx, request := c.streamRequest(ctx)
+		stream, err = c.client.SubscribeSignedVAA(streamCtx, request)
+		if err == nil {
+			return stream, nil
+		}
+
+		if attempt < maxRetries {
+			c.logger.Warn("Subscribe attempt failed",
+				zap.Int("attempt", attempt),
+				zap.Error(err),
+				zap.Duration("retryIn", retryDelay))
+
+			select {
+			case <-time.After(retryDelay):
+				// Continue to next retry
+			case <-ctx.Done():
+				return nil, fmt.Errorf("context cancelled during retry: %v", ctx.Err())
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("failed to subscribe after %d attempts: %v", maxRetries, err)
+}
@@ -0,0 +1,89 @@
+// Package wormholescan fetches signed VAAs from the Wormholescan REST API, or any service that
+// serves the same GET /v1/signed_vaa/{chain}/{emitter}/{sequence} endpoint.
+package wormholescan
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// ErrVAANotFound is returned when the API has no VAA for the requested sequence
+var ErrVAANotFound = errors.New("signed VAA not found")
+
+// signedVAAResponse is the body of GET /v1/signed_vaa
+type signedVAAResponse struct {
+	VAABytes string `json:"vaaBytes"` // Base64
+}
+
+// Client is a Wormholescan API client
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	logger     *zap.Logger
+}
+
+// NewClient creates a client of the API at baseURL, e.g. https://api.wormholescan.io/api
+func NewClient(baseURL string, logger *zap.Logger) *Client {
+	return &Client{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+		logger: logger.With(zap.String("component", "WormholescanClient")),
+	}
+}
+
+// GetSignedVAA returns the signed VAA with sequence from emitterHex (32 bytes of hex) on chainID
+func (c *Client) GetSignedVAA(ctx context.Context, chainID uint16, emitterHex string, sequence uint64) ([]byte, error) {
+	url := fmt.Sprintf("%s/v1/signed_vaa/%d/%s/%d", c.baseURL, chainID, strings.TrimPrefix(emitterHex, "0x"), sequence)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP request: %v", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch signed VAA: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signed VAA response: %v", err)
+	}
+
+	c.logger.Debug("Fetched signed VAA",
+		zap.Uint16("chain", chainID),
+		zap.String("emitter", emitterHex),
+		zap.Uint64("sequence", sequence),
+		zap.Int("statusCode", resp.StatusCode))
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, ErrVAANotFound
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("signed VAA request failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var response signedVAAResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal signed VAA response: %v", err)
+	}
+	if response.VAABytes == "" {
+		return nil, ErrVAANotFound
+	}
+	vaaBytes, err := base64.StdEncoding.DecodeString(response.VAABytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base64 VAA: %v", err)
+	}
+	return vaaBytes, nil
+}